@@ -27,6 +27,7 @@ import (
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"go.uber.org/goleak"
+	"golang.org/x/oauth2"
 	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
 )
@@ -34,42 +35,17 @@ import (
 var (
 	offset        int
 	sheetName     string
-	credFilePath  string
-	tokenFilePath string
 	sheetURL      string
 	spreadsheetID string
 	sheetID       int64
 )
 
 func TestAcceptance(t *testing.T) {
-	credJSON := strings.TrimSpace(os.Getenv("CONDUIT_GOOGLE_CREDENTIAL_JSON"))
-	if credJSON != "" {
-		credFile, err := os.CreateTemp("", "cred*.json")
-		if err != nil {
-			t.Fatal(err)
-		}
-		defer os.Remove(credFilePath)
-		if _, err = credFile.WriteString(credJSON); err != nil {
-			t.Error("error writing cred file", err)
-		}
-		credFilePath = credFile.Name()
-	} else {
+	if strings.TrimSpace(os.Getenv("CONDUIT_GOOGLE_CREDENTIAL_JSON")) == "" {
 		t.Skip("credentials not set in env CONDUIT_GOOGLE_CREDENTIAL_JSON")
 	}
 
-	tokenJSON := strings.TrimSpace(os.Getenv("CONDUIT_GOOGLE_TOKEN_JSON"))
-	if tokenJSON != "" {
-		tokenFile, err := os.CreateTemp("", "token*.json")
-		if err != nil {
-			t.Fatal(err)
-		}
-		defer os.Remove(tokenFilePath)
-
-		if _, err = tokenFile.WriteString(tokenJSON); err != nil {
-			t.Error("error writing token file", err)
-		}
-		tokenFilePath = tokenFile.Name()
-	} else {
+	if strings.TrimSpace(os.Getenv("CONDUIT_GOOGLE_TOKEN_JSON")) == "" {
 		t.Error("token not set in env CONDUIT_GOOGLE_TOKEN_JSON")
 		t.FailNow()
 	}
@@ -86,16 +62,17 @@ func TestAcceptance(t *testing.T) {
 		t.FailNow()
 	}
 
+	// credentials and tokens are resolved from the env variables by the connector
 	sourceConfig := map[string]string{
-		"credentialsFile": credFilePath,
-		"tokensFile":      tokenFilePath,
+		"credentialsJSON": "${CONDUIT_GOOGLE_CREDENTIAL_JSON}",
+		"tokensJSON":      "${CONDUIT_GOOGLE_TOKEN_JSON}",
 		"sheetsURL":       sheetURL,
 		"pollingPeriod":   "1s", // Configurable polling period
 	}
 
 	destConfig := map[string]string{
-		"credentialsFile":  credFilePath,
-		"tokensFile":       tokenFilePath,
+		"credentialsJSON":  "${CONDUIT_GOOGLE_CREDENTIAL_JSON}",
+		"tokensJSON":       "${CONDUIT_GOOGLE_TOKEN_JSON}",
 		"sheetsURL":        sheetURL,
 		"sheetName":        sheetName,
 		"valueInputOption": "USER_ENTERED",
@@ -103,7 +80,8 @@ func TestAcceptance(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	conf, err := config.Parse(sourceConfig)
+	// the sheet is cleared between the tests, which needs write access
+	conf, err := config.Parse(sourceConfig, config.ScopeSpreadsheets)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -111,7 +89,12 @@ func TestAcceptance(t *testing.T) {
 	spreadsheetID = conf.GoogleSpreadsheetID
 	sheetID = conf.GoogleSheetID
 
-	client := conf.OAuthConfig.Client(ctx, conf.OAuthToken)
+	tokenSource, err := conf.TokenSource(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := oauth2.NewClient(ctx, tokenSource)
 	sheetService, err := sheets.NewService(ctx, option.WithHTTPClient(client))
 	if err != nil {
 		t.Fatal(err)
@@ -30,16 +30,19 @@ import (
 	"sync"
 	"time"
 
+	sheetsconfig "github.com/conduitio-labs/conduit-connector-google-sheets/config"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
 
 var (
+	// scopes requested for the token, read-write by default as needed by the destination connector
 	scopes = []string{
-		"https://www.googleapis.com/auth/spreadsheets.readonly",
-		"https://www.googleapis.com/auth/spreadsheets",
+		sheetsconfig.ScopeSpreadsheets,
 	}
+	readOnly              bool
+	driveFolder           bool
 	defaultCredentialFile = "./credentials.json"
 	credFile              string
 	config                *oauth2.Config
@@ -65,8 +68,17 @@ func init() {
 	flag.StringVar(&authCode, "code", "", "generate token from auth code, if already available, and don't start the redirect server")
 	flag.StringVar(&port, "port", "3000", "url port to start redirect URI listener at, default: 3000")
 	flag.StringVar(&host, "host", "127.0.0.1", "url host to start redirect URI listener at, default: 127.0.0.1")
+	flag.BoolVar(&readOnly, "readonly", false, "generate a token with read-only access, sufficient for the source connector only")
+	flag.BoolVar(&driveFolder, "drive", false, "also grant listing the Drive files, needed by the source reading a Drive folder")
 
 	flag.Parse()
+
+	if readOnly {
+		scopes = []string{sheetsconfig.ScopeSpreadsheetsReadOnly}
+	}
+	if driveFolder {
+		scopes = append(scopes, sheetsconfig.ScopeDriveMetadataReadOnly)
+	}
 }
 
 func main() {
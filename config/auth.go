@@ -0,0 +1,275 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// AuthMode is the way the connector authenticates against the Google APIs
+type AuthMode string
+
+const (
+	// AuthModeOAuth uses an installed/web OAuth client(credentialsFile) along with the user token(tokensFile)
+	AuthModeOAuth AuthMode = "oauth"
+	// AuthModeServiceAccount uses a service account JSON key(credentialsFile), no tokens file is needed
+	AuthModeServiceAccount AuthMode = "serviceAccount"
+	// AuthModeApplicationDefault uses Google's Application Default Credentials discovery, e.g. the
+	// GOOGLE_APPLICATION_CREDENTIALS env variable, gcloud credentials or the GKE/GCE metadata server.
+	// credentialsFile is optional and can point to a workload identity federation(external_account) config
+	AuthModeApplicationDefault AuthMode = "applicationDefault"
+)
+
+const (
+	// credentialsTypeServiceAccount is the value of the `type` field in a service account JSON key
+	credentialsTypeServiceAccount = "service_account"
+	// credentialsTypeExternalAccount is the value of the `type` field in a workload identity federation config
+	credentialsTypeExternalAccount = "external_account"
+)
+
+// TokenSource returns the token source for the configured auth mode,
+// the returned token source is used to build the HTTP client for the Google APIs
+func (c Config) TokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	switch c.AuthMode {
+	case AuthModeServiceAccount:
+		ts := c.JWTConfig.TokenSource(ctx)
+		if c.JWTConfig.Subject != "" {
+			// Google validates the domain-wide delegation only when a token is requested,
+			// fetch one eagerly so a misconfigured delegation fails at Open instead of the first API call
+			if _, err := ts.Token(); err != nil {
+				return nil, delegationErr(c.JWTConfig.Subject, err)
+			}
+		}
+		return ts, nil
+	case AuthModeApplicationDefault:
+		creds, err := c.applicationDefaultCredentials(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find application default credentials: %w", err)
+		}
+		return creds.TokenSource, nil
+	default:
+		ts, err := sharedTokenSource(ctx, c.TokensFile, c.OAuthConfig, c.OAuthToken)
+		if err != nil {
+			return nil, err
+		}
+		// the scopes of a user token are decided when the token is generated, verify they cover the connector needs
+		if err := validateTokenScopes(ctx, ts, c.Scopes, c.OAuthConfig.Endpoint.TokenURL); err != nil {
+			return nil, err
+		}
+		return ts, nil
+	}
+}
+
+func (c Config) applicationDefaultCredentials(ctx context.Context) (*google.Credentials, error) {
+	if len(c.ExternalAccountConfig) == 0 {
+		return google.FindDefaultCredentials(ctx, c.Scopes...)
+	}
+	return google.CredentialsFromJSONWithType(ctx, c.ExternalAccountConfig, google.ExternalAccount, c.Scopes...)
+}
+
+// parseAuth parses the auth related config values, based on the auth mode
+func parseAuth(config map[string]string, scopes []string) (Config, error) {
+	authMode := AuthMode(strings.TrimSpace(config[KeyAuthMode]))
+	switch authMode {
+	case "", AuthModeOAuth, AuthModeServiceAccount:
+	case AuthModeApplicationDefault:
+		return parseApplicationDefault(config)
+	default:
+		return Config{}, fmt.Errorf(
+			"invalid value received for config(`%s`):`%s`, should be oneof [`%s`, `%s`, `%s`]",
+			KeyAuthMode, authMode, AuthModeOAuth, AuthModeServiceAccount, AuthModeApplicationDefault,
+		)
+	}
+
+	// parse credentials.json
+	credBytes, err := readJSONConfig(config, KeyCredentialsFile, KeyCredentialsJSON, "client secret file")
+	if err != nil {
+		return Config{}, err
+	}
+	// check if configs exist
+	if credBytes == nil {
+		return Config{}, requiredOneOfConfigErr(KeyCredentialsFile, KeyCredentialsJSON)
+	}
+
+	subject := strings.TrimSpace(config[KeyImpersonateSubject])
+	isServiceAccount := credentialsType(credBytes) == credentialsTypeServiceAccount
+	switch {
+	case authMode == AuthModeOAuth && isServiceAccount:
+		return Config{}, fmt.Errorf("%q auth mode requires an OAuth client credentials file, got a service account key", authMode)
+	case authMode == AuthModeServiceAccount && !isServiceAccount:
+		return Config{}, fmt.Errorf("%q auth mode requires a service account key credentials file", authMode)
+	case isServiceAccount:
+		return parseServiceAccount(credBytes, subject, scopes)
+	case subject != "":
+		return Config{}, fmt.Errorf("%q config value is only supported for service account keys", KeyImpersonateSubject)
+	default:
+		return parseOAuth(credBytes, config, scopes)
+	}
+}
+
+func parseOAuth(credBytes []byte, config map[string]string, scopes []string) (Config, error) {
+	if config[KeyTokensFile] == "" && config[KeyTokensJSON] == "" {
+		return Config{}, requiredOneOfConfigErr(KeyTokensFile, KeyTokensJSON)
+	}
+
+	// validate if the credentials are google credentials
+	oauthConfig, err := google.ConfigFromJSON(credBytes, scopes...)
+	if err != nil {
+		return Config{}, fmt.Errorf("unable to parse client secret file to config: %w", err)
+	}
+
+	// parse tokens file
+	var token *oauth2.Token
+	tokenBytes, err := readJSONConfig(config, KeyTokensFile, KeyTokensJSON, "tokens file")
+	if err != nil {
+		return Config{}, err
+	}
+
+	if err := json.Unmarshal(tokenBytes, &token); err != nil {
+		return Config{}, fmt.Errorf("unable to unmarshal tokens: %w", err)
+	}
+
+	return Config{
+		AuthMode:    AuthModeOAuth,
+		OAuthConfig: oauthConfig,
+		OAuthToken:  token,
+		// inline tokens can't be written back, TokensFile is empty in that case
+		TokensFile: config[KeyTokensFile],
+	}, nil
+}
+
+func parseServiceAccount(credBytes []byte, subject string, scopes []string) (Config, error) {
+	jwtConfig, err := google.JWTConfigFromJSON(credBytes, scopes...)
+	if err != nil {
+		return Config{}, fmt.Errorf("unable to parse service account key file to config: %w", err)
+	}
+	jwtConfig.Subject = subject
+	return Config{
+		AuthMode:  AuthModeServiceAccount,
+		JWTConfig: jwtConfig,
+	}, nil
+}
+
+// parseApplicationDefault validates the config for AuthModeApplicationDefault, the credentials themselves
+// are discovered on Open, as the discovery may need to reach the metadata server
+func parseApplicationDefault(config map[string]string) (Config, error) {
+	if strings.TrimSpace(config[KeyImpersonateSubject]) != "" {
+		return Config{}, fmt.Errorf("%q config value is only supported for service account keys", KeyImpersonateSubject)
+	}
+
+	cfg := Config{AuthMode: AuthModeApplicationDefault}
+	credBytes, err := readJSONConfig(config, KeyCredentialsFile, KeyCredentialsJSON, "external account config file")
+	if err != nil {
+		return Config{}, err
+	}
+	if credBytes == nil {
+		return cfg, nil
+	}
+	if credType := credentialsType(credBytes); credType != credentialsTypeExternalAccount {
+		return Config{}, fmt.Errorf(
+			"%q auth mode only accepts a workload identity federation(%s) config in %q, got type %q",
+			AuthModeApplicationDefault, credentialsTypeExternalAccount, KeyCredentialsFile, credType,
+		)
+	}
+	cfg.ExternalAccountConfig = credBytes
+	return cfg, nil
+}
+
+// readJSONConfig returns the JSON content of either the file config value or the inline JSON config value,
+// only one of them can be set. It returns nil if neither is set
+func readJSONConfig(config map[string]string, fileKey, jsonKey, fileDesc string) ([]byte, error) {
+	inlineJSON, err := resolveEnvRef(jsonKey, config[jsonKey])
+	if err != nil {
+		return nil, err
+	}
+
+	file := config[fileKey]
+	switch {
+	case file != "" && inlineJSON != "":
+		return nil, fmt.Errorf("%q and %q config values are mutually exclusive", fileKey, jsonKey)
+	case inlineJSON != "":
+		return []byte(inlineJSON), nil
+	case file != "":
+		fileBytes, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", fileDesc, err)
+		}
+		return fileBytes, nil
+	default:
+		return nil, nil
+	}
+}
+
+// resolveEnvRef resolves a `${ENV_NAME}` config value to the value of the env variable,
+// any other value is returned as is
+func resolveEnvRef(key, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	matches := envRefRegexp.FindStringSubmatch(value)
+	if matches == nil {
+		return value, nil
+	}
+	envValue := strings.TrimSpace(os.Getenv(matches[1]))
+	if envValue == "" {
+		return "", fmt.Errorf("%q config value references env variable %q, which is not set", key, matches[1])
+	}
+	return envValue, nil
+}
+
+// credentialsType returns the `type` field of the credentials JSON, e.g. service account keys
+// are identified by `"type": "service_account"`, OAuth client credentials don't have the field
+func credentialsType(credBytes []byte) string {
+	var cred struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(credBytes, &cred); err != nil {
+		return ""
+	}
+	return cred.Type
+}
+
+// delegationErr converts the token endpoint errors, returned for an impersonated subject,
+// into a configuration error pointing at the domain-wide delegation setup
+func delegationErr(subject string, err error) error {
+	var rerr *oauth2.RetrieveError
+	if !errors.As(err, &rerr) {
+		return fmt.Errorf("unable to get token for impersonated subject %q: %w", subject, err)
+	}
+
+	code := rerr.ErrorCode
+	if code == "" {
+		// the jwt token source doesn't decode the error response, read the RFC 6749 `error` field from the body
+		var body struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(rerr.Body, &body)
+		code = body.Error
+	}
+	if code == "unauthorized_client" || code == "access_denied" || code == "invalid_grant" {
+		return fmt.Errorf(
+			"invalid %q config: service account is not allowed to impersonate %q, "+
+				"check the domain-wide delegation and the OAuth scopes granted to the service account client ID: %w",
+			KeyImpersonateSubject, subject, err,
+		)
+	}
+	return fmt.Errorf("unable to get token for impersonated subject %q: %w", subject, err)
+}
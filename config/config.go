@@ -15,14 +15,15 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
+	"net/url"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
+	"golang.org/x/oauth2/jwt"
 )
 
 const (
@@ -32,97 +33,277 @@ const (
 	// KeyTokensFile is the config name for google generated token file
 	KeyTokensFile = "tokensFile"
 
-	// KeySheetURL is the config name for google-sheets url
+	// KeyCredentialsJSON is the config name for the inline Google access key JSON,
+	// mutually exclusive with KeyCredentialsFile
+	KeyCredentialsJSON = "credentialsJSON"
+
+	// KeyTokensJSON is the config name for the inline google generated token JSON,
+	// mutually exclusive with KeyTokensFile
+	KeyTokensJSON = "tokensJSON"
+
+	// KeySheetURL is the config name for google-sheets url, or the bare spreadsheet ID,
+	// a comma separated list for the source reading several spreadsheets
 	KeySheetURL = "sheetsURL"
+
+	// KeyDriveFolderID is the config name for the Drive folder ID, the source reads every spreadsheet in the folder,
+	// mutually exclusive with KeySheetURL
+	KeyDriveFolderID = "driveFolderID"
+
+	// KeySheetID is the config name for the sheet(tab) gid, an alternative to the gid in the sheets url
+	KeySheetID = "sheetID"
+
+	// KeySheetName is the config name for the sheet(tab) title
+	KeySheetName = "sheetName"
+
+	// KeyAuthMode is the config name for the authentication mode,
+	// detected from the credentials file when not set
+	KeyAuthMode = "authMode"
+
+	// KeyImpersonateSubject is the config name for the user email impersonated by the service account,
+	// using domain-wide delegation
+	KeyImpersonateSubject = "impersonateSubject"
+
+	// KeyEndpoint is the config name for the base URL of the Sheets API, e.g. a local emulator
+	KeyEndpoint = "endpoint"
+
+	// KeyProxyURL is the config name for the HTTP proxy used for the Google API requests,
+	// the proxy is taken from the HTTPS_PROXY/HTTP_PROXY environment variables when not set
+	KeyProxyURL = "proxyURL"
+
+	// KeyRequestTimeout is the config name for the timeout of a single Google API request
+	KeyRequestTimeout = "requestTimeout"
+
+	// KeyCACertFile is the config name for the PEM encoded CA bundle trusted in addition to the system roots
+	KeyCACertFile = "caCertFile"
+
+	// KeyQuotaProject is the config name for the GCP project the API quota and billing are attributed to
+	KeyQuotaProject = "quotaProject"
+)
+
+const (
+	// ScopeSpreadsheetsReadOnly is the OAuth scope required to read the spreadsheets, used by the source connector
+	ScopeSpreadsheetsReadOnly = "https://www.googleapis.com/auth/spreadsheets.readonly"
+	// ScopeSpreadsheets is the OAuth scope required to read and write the spreadsheets, used by the destination connector
+	ScopeSpreadsheets = "https://www.googleapis.com/auth/spreadsheets"
+	// ScopeDriveMetadataReadOnly is the OAuth scope required to list the spreadsheets of a Drive folder
+	ScopeDriveMetadataReadOnly = "https://www.googleapis.com/auth/drive.metadata.readonly"
 )
 
 var (
-	// scopes for spreadsheets are required in order to access the google SheetAPI.
-	scopes = []string{
-		"https://www.googleapis.com/auth/spreadsheets.readonly",
-		"https://www.googleapis.com/auth/spreadsheets",
-	}
-	sheetsRegexp = regexp.MustCompile(`\/spreadsheets\/d\/([a-zA-Z0-9-_]+)\/(.*)#gid=([0-9]+)`)
+	// sheetsRegexp matches the spreadsheet ID in a Google Sheets URL
+	sheetsRegexp = regexp.MustCompile(`\/spreadsheets\/d\/([a-zA-Z0-9-_]+)`)
+	// spreadsheetIDRegexp matches a bare spreadsheet ID
+	spreadsheetIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9-_]+$`)
+	// driveFolderRegexp matches the folder ID in a Google Drive folder URL
+	driveFolderRegexp = regexp.MustCompile(`\/folders\/([a-zA-Z0-9-_]+)`)
+	// envRefRegexp matches the `${ENV_NAME}` config values, resolved from the environment
+	envRefRegexp = regexp.MustCompile(`^\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}$`)
 )
 
+// NoSheetID is the GoogleSheetID value when the gid isn't set in the config,
+// the gid is then resolved from the spreadsheet metadata
+const NoSheetID int64 = -1
+
+// SpreadsheetLocator locates a spreadsheet, and its sheet(tab) gid, NoSheetID if it isn't set
+type SpreadsheetLocator struct {
+	SpreadsheetID string
+	SheetID       int64
+}
+
 // Config represent configuration needed for google-sheets
 type Config struct {
-	OAuthConfig         *oauth2.Config
-	OAuthToken          *oauth2.Token
-	GoogleSpreadsheetID string
-	GoogleSheetID       int64
+	AuthMode AuthMode
+	// OAuthConfig, OAuthToken and TokensFile are set in AuthModeOAuth,
+	// refreshed tokens are written back to TokensFile
+	OAuthConfig *oauth2.Config
+	OAuthToken  *oauth2.Token
+	TokensFile  string
+	// JWTConfig is set in AuthModeServiceAccount
+	JWTConfig *jwt.Config
+	// Scopes are the OAuth scopes requested for, and required from, the credentials
+	Scopes []string
+	// ExternalAccountConfig is the optional workload identity federation config used in AuthModeApplicationDefault,
+	// the credentials are discovered from the environment when it's empty
+	ExternalAccountConfig []byte
+	GoogleSpreadsheetID   string
+	// GoogleSheetID is the gid of the sheet(tab), NoSheetID when only the sheet name is known,
+	// or neither, in which case the first sheet of the spreadsheet is used
+	GoogleSheetID   int64
+	GoogleSheetName string
+	// ExtraSpreadsheets are the spreadsheets listed after the first one in the sheets url, nil for a single spreadsheet
+	ExtraSpreadsheets []SpreadsheetLocator
+	// DriveFolderID is the Drive folder whose spreadsheets are read, GoogleSpreadsheetID is then empty
+	// and GoogleSheetID applies to every spreadsheet of the folder
+	DriveFolderID string
+
+	// Endpoint, ProxyURL, RequestTimeout and CABundle configure the HTTP client used for the Google APIs,
+	// zero values keep the defaults
+	Endpoint       string
+	ProxyURL       *url.URL
+	RequestTimeout time.Duration
+	CABundle       []byte
+	// QuotaProject is the project sent in the x-goog-user-project header, empty to use the credentials' project
+	QuotaProject string
 }
 
-// Parse attempts to parse plugins.Config into a Config struct
-func Parse(config map[string]string) (Config, error) {
-	// check if configs exist
-	credFile := config[KeyCredentialsFile]
-	if credFile == "" {
-		return Config{}, requiredConfigErr(KeyCredentialsFile)
+// Parse attempts to parse plugins.Config into a Config struct,
+// scopes are the OAuth scopes needed by the connector using the config
+func Parse(config map[string]string, scopes ...string) (Config, error) {
+	sheetURL := config[KeySheetURL]
+	driveFolderID := strings.TrimSpace(config[KeyDriveFolderID])
+	if driveFolderID != "" {
+		if strings.TrimSpace(sheetURL) != "" {
+			return Config{}, fmt.Errorf("%q config value can't be used with %q", KeyDriveFolderID, KeySheetURL)
+		}
+		// listing the folder needs the Drive scope on top of the connector scopes
+		scopes = append(scopes[:len(scopes):len(scopes)], ScopeDriveMetadataReadOnly)
 	}
 
-	tokenFile := config[KeyTokensFile]
-	if tokenFile == "" {
-		return Config{}, requiredConfigErr(KeyTokensFile)
+	cfg, err := parseAuth(config, scopes)
+	if err != nil {
+		// skip wrapping error, getting wrapped error from the auth parsing functions
+		return Config{}, err
 	}
 
-	sheetURL := config[KeySheetURL]
+	if driveFolderID != "" {
+		return parseDriveFolder(config, cfg, driveFolderID, scopes)
+	}
 	if sheetURL == "" {
 		return Config{}, requiredConfigErr(KeySheetURL)
 	}
 
-	// parse credentials.json
-	credBytes, err := os.ReadFile(credFile)
-	if err != nil {
-		return Config{}, fmt.Errorf("unable to read client secret file: %w", err)
+	configSheetID := NoSheetID
+	if sheetIDStr := strings.TrimSpace(config[KeySheetID]); sheetIDStr != "" {
+		configSheetID, err = strconv.ParseInt(sheetIDStr, 10, 64)
+		if err != nil || configSheetID < 0 {
+			return Config{}, fmt.Errorf("%q config value should be a non-negative integer", KeySheetID)
+		}
 	}
 
-	// validate if the credentials are google credentials
-	oauthConfig, err := google.ConfigFromJSON(credBytes, scopes...)
-	if err != nil {
-		return Config{}, fmt.Errorf("unable to parse client secret file to config: %w", err)
+	// parse sheets urls, a comma separated list of spreadsheets
+	spreadsheets := make([]SpreadsheetLocator, 0, 1)
+	seen := make(map[string]bool)
+	for _, spreadsheetURL := range strings.Split(sheetURL, ",") {
+		if strings.TrimSpace(spreadsheetURL) == "" {
+			continue
+		}
+		spreadSheetID, sheetID, err := parseSheetURL(spreadsheetURL)
+		if err != nil {
+			// skip wrapping error, getting wrapped error from parseSheetURL function
+			return Config{}, err
+		}
+		if configSheetID != NoSheetID {
+			if sheetID != NoSheetID && sheetID != configSheetID {
+				return Config{}, fmt.Errorf(
+					"%q config value(%d) doesn't match the gid(%d) in %q", KeySheetID, configSheetID, sheetID, KeySheetURL,
+				)
+			}
+			sheetID = configSheetID
+		}
+		if seen[spreadSheetID] {
+			return Config{}, fmt.Errorf("spreadsheet(%s) is listed more than once in %q", spreadSheetID, KeySheetURL)
+		}
+		seen[spreadSheetID] = true
+		spreadsheets = append(spreadsheets, SpreadsheetLocator{SpreadsheetID: spreadSheetID, SheetID: sheetID})
+	}
+	if len(spreadsheets) == 0 {
+		return Config{}, requiredConfigErr(KeySheetURL)
 	}
 
-	// parse tokens file
-	var token *oauth2.Token
-	tokenBytes, err := os.ReadFile(tokenFile)
-	if err != nil {
-		return Config{}, fmt.Errorf("unable to read tokens file: %w", err)
+	if err := parseTransport(config, &cfg); err != nil {
+		// skip wrapping error, getting wrapped error from parseTransport function
+		return Config{}, err
 	}
 
-	if err := json.Unmarshal(tokenBytes, &token); err != nil {
-		return Config{}, fmt.Errorf("unable to unmarshal tokens file: %w", err)
+	cfg.QuotaProject = strings.TrimSpace(config[KeyQuotaProject])
+	cfg.Scopes = scopes
+	cfg.GoogleSpreadsheetID = spreadsheets[0].SpreadsheetID
+	cfg.GoogleSheetID = spreadsheets[0].SheetID
+	cfg.GoogleSheetName = strings.TrimSpace(config[KeySheetName])
+	if len(spreadsheets) > 1 {
+		cfg.ExtraSpreadsheets = spreadsheets[1:]
 	}
+	return cfg, nil
+}
 
-	// parse sheets url
-	spreadSheetID, sheetID, err := parseSheetURL(sheetURL)
+// parseDriveFolder completes the config of the source reading the spreadsheets of a Drive folder
+func parseDriveFolder(config map[string]string, cfg Config, driveFolderID string, scopes []string) (Config, error) {
+	folderID, err := parseDriveFolderURL(driveFolderID)
 	if err != nil {
-		// skip wrapping error, getting wrapped error from parseSheetURL function
+		// skip wrapping error, getting wrapped error from parseDriveFolderURL function
 		return Config{}, err
 	}
 
-	cfg := Config{
-		OAuthConfig:         oauthConfig,
-		OAuthToken:          token,
-		GoogleSheetID:       sheetID,
-		GoogleSpreadsheetID: spreadSheetID,
+	cfg.GoogleSheetID = NoSheetID
+	if sheetIDStr := strings.TrimSpace(config[KeySheetID]); sheetIDStr != "" {
+		cfg.GoogleSheetID, err = strconv.ParseInt(sheetIDStr, 10, 64)
+		if err != nil || cfg.GoogleSheetID < 0 {
+			return Config{}, fmt.Errorf("%q config value should be a non-negative integer", KeySheetID)
+		}
 	}
+
+	if err := parseTransport(config, &cfg); err != nil {
+		// skip wrapping error, getting wrapped error from parseTransport function
+		return Config{}, err
+	}
+
+	cfg.QuotaProject = strings.TrimSpace(config[KeyQuotaProject])
+	cfg.Scopes = scopes
+	cfg.DriveFolderID = folderID
+	cfg.GoogleSheetName = strings.TrimSpace(config[KeySheetName])
 	return cfg, nil
 }
 
+// parseDriveFolderURL parses the folder ID from a Google Drive folder URL,
+// e.g. https://drive.google.com/drive/folders/<id>, or a bare folder ID
+func parseDriveFolderURL(folderURL string) (string, error) {
+	if spreadsheetIDRegexp.MatchString(folderURL) {
+		return folderURL, nil
+	}
+
+	stringMatches := driveFolderRegexp.FindStringSubmatch(folderURL)
+	if len(stringMatches) != 2 {
+		return "", fmt.Errorf("invalid %q config value, should be a Google Drive folder URL or a folder ID", KeyDriveFolderID)
+	}
+	return stringMatches[1], nil
+}
+
 func requiredConfigErr(name string) error {
 	return fmt.Errorf("%q config value must be set", name)
 }
 
-func parseSheetURL(url string) (string, int64, error) {
-	if !sheetsRegexp.MatchString(url) {
-		return "", 0, fmt.Errorf("invalid url passed, should match regex: %s", sheetsRegexp.String())
+func requiredOneOfConfigErr(name, altName string) error {
+	return fmt.Errorf("%q or %q config value must be set", name, altName)
+}
+
+// parseSheetURL parses the spreadsheet ID, and the sheet gid if present, from a Google Sheets URL
+// e.g. https://docs.google.com/spreadsheets/d/<id>/edit#gid=<gid> or .../edit?gid=<gid>, or a bare spreadsheet ID
+func parseSheetURL(sheetURL string) (string, int64, error) {
+	sheetURL = strings.TrimSpace(sheetURL)
+	if spreadsheetIDRegexp.MatchString(sheetURL) {
+		return sheetURL, NoSheetID, nil
 	}
-	stringMatches := sheetsRegexp.FindStringSubmatch(url)
-	if len(stringMatches) != 4 {
-		return "", 0, fmt.Errorf("invalid url, required 4 parts, got %d", len(stringMatches))
+
+	stringMatches := sheetsRegexp.FindStringSubmatch(sheetURL)
+	if len(stringMatches) != 2 {
+		return "", 0, fmt.Errorf("invalid %q config value, should be a Google Sheets URL or a spreadsheet ID", KeySheetURL)
 	}
-	sheetID, err := strconv.ParseInt(stringMatches[3], 10, 64)
+
+	parsedURL, err := url.Parse(sheetURL)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid url passed: %w", err)
+	}
+
+	// the gid is either in the fragment(#gid=<gid>) or in the query(?gid=<gid>)
+	gid := parsedURL.Query().Get("gid")
+	if fragment, err := url.ParseQuery(parsedURL.Fragment); err == nil && fragment.Get("gid") != "" {
+		gid = fragment.Get("gid")
+	}
+	if gid == "" {
+		return stringMatches[1], NoSheetID, nil
+	}
+
+	sheetID, err := strconv.ParseInt(gid, 10, 64)
 	if err != nil {
 		return "", 0, fmt.Errorf("error converting sheet id to int: %w", err)
 	}
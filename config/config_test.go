@@ -15,19 +15,33 @@
 package config
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2/jwt"
 )
 
 func TestParse(t *testing.T) {
 	filePath := getFilePath("conduit-connector-google-sheets") // Get project root path
 	validCredFile := fmt.Sprintf("%s/testdata/dummy_cred.json", filePath)
 	invalidCredFile := fmt.Sprintf("%s/testdata/dummy_invalid_cred.json", filePath)
+	serviceAccountFile := fmt.Sprintf("%s/testdata/dummy_service_account.json", filePath)
+	externalAccountFile := fmt.Sprintf("%s/testdata/dummy_external_account.json", filePath)
+	credJSON, _ := os.ReadFile(validCredFile)
+	tokenJSON, _ := os.ReadFile(fmt.Sprintf("%s/testdata/dummy_token.json", filePath))
+	t.Setenv("CONDUIT_TEST_GOOGLE_CREDENTIAL_JSON", string(credJSON))
+	t.Setenv("CONDUIT_TEST_GOOGLE_TOKEN_JSON", string(tokenJSON))
 	tests := []struct {
 		name   string
 		config map[string]string
@@ -36,7 +50,7 @@ func TestParse(t *testing.T) {
 	}{{
 		name:   "missing required params",
 		config: map[string]string{},
-		err:    fmt.Errorf(`"credentialsFile" config value must be set`),
+		err:    fmt.Errorf(`"credentialsFile" or "credentialsJSON" config value must be set`),
 		want:   Config{},
 	}, {
 		name: "config succeeds",
@@ -47,16 +61,104 @@ func TestParse(t *testing.T) {
 		},
 		err: nil,
 		want: Config{
+			AuthMode:            AuthModeOAuth,
+			TokensFile:          validCredFile,
 			GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
 			GoogleSheetID:       158080911,
 		},
+	}, {
+		name: "service account config succeeds without tokens file",
+		config: map[string]string{
+			KeyCredentialsFile: serviceAccountFile,
+			KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:            AuthModeServiceAccount,
+			GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+			GoogleSheetID:       158080911,
+		},
+	}, {
+		name: "service account config with impersonated subject",
+		config: map[string]string{
+			KeyCredentialsFile:    serviceAccountFile,
+			KeyImpersonateSubject: "user@example.com",
+			KeySheetURL:           "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:            AuthModeServiceAccount,
+			GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+			GoogleSheetID:       158080911,
+		},
+	}, {
+		name: "impersonated subject with oauth credentials",
+		config: map[string]string{
+			KeyTokensFile:         validCredFile,
+			KeyCredentialsFile:    validCredFile,
+			KeyImpersonateSubject: "user@example.com",
+			KeySheetURL:           "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+		},
+		err:  fmt.Errorf(`"impersonateSubject" config value is only supported for service account keys`),
+		want: Config{},
+	}, {
+		name: "application default credentials without files",
+		config: map[string]string{
+			KeyAuthMode: "applicationDefault",
+			KeySheetURL: "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:            AuthModeApplicationDefault,
+			GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+			GoogleSheetID:       158080911,
+		},
+	}, {
+		name: "application default credentials with external account config",
+		config: map[string]string{
+			KeyAuthMode:        "applicationDefault",
+			KeyCredentialsFile: externalAccountFile,
+			KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:            AuthModeApplicationDefault,
+			GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+			GoogleSheetID:       158080911,
+		},
+	}, {
+		name: "application default credentials with service account key",
+		config: map[string]string{
+			KeyAuthMode:        "applicationDefault",
+			KeyCredentialsFile: serviceAccountFile,
+			KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+		},
+		err:  fmt.Errorf(`"applicationDefault" auth mode only accepts a workload identity federation(external_account) config in "credentialsFile", got type "service_account"`),
+		want: Config{},
+	}, {
+		name: "service account auth mode with oauth credentials",
+		config: map[string]string{
+			KeyAuthMode:        "serviceAccount",
+			KeyTokensFile:      validCredFile,
+			KeyCredentialsFile: validCredFile,
+			KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+		},
+		err:  fmt.Errorf(`"serviceAccount" auth mode requires a service account key credentials file`),
+		want: Config{},
+	}, {
+		name: "invalid auth mode",
+		config: map[string]string{
+			KeyAuthMode: "apiKey",
+		},
+		err:  fmt.Errorf("invalid value received for config(`authMode`):`apiKey`, should be oneof [`oauth`, `serviceAccount`, `applicationDefault`]"),
+		want: Config{},
 	}, {
 		name: "missing required token file params",
 		config: map[string]string{
 			KeyCredentialsFile: validCredFile,
 			KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
 		},
-		err:  fmt.Errorf(`"tokensFile" config value must be set`),
+		err:  fmt.Errorf(`"tokensFile" or "tokensJSON" config value must be set`),
 		want: Config{},
 	}, {
 		name: "missing required sheets url params",
@@ -71,9 +173,156 @@ func TestParse(t *testing.T) {
 		config: map[string]string{
 			KeyTokensFile:      validCredFile,
 			KeyCredentialsFile: validCredFile,
-			KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit",
+			KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit?usp=sharing",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:            AuthModeOAuth,
+			TokensFile:          validCredFile,
+			GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+			GoogleSheetID:       NoSheetID,
+		},
+	}, {
+		name: "gid in sheets url query",
+		config: map[string]string{
+			KeyTokensFile:      validCredFile,
+			KeyCredentialsFile: validCredFile,
+			KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit?gid=0",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:            AuthModeOAuth,
+			TokensFile:          validCredFile,
+			GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+			GoogleSheetID:       0,
+		},
+	}, {
+		name: "quota project",
+		config: map[string]string{
+			KeyTokensFile:      validCredFile,
+			KeyCredentialsFile: validCredFile,
+			KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=0",
+			KeyQuotaProject:    " billing-project ",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:            AuthModeOAuth,
+			TokensFile:          validCredFile,
+			GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+			GoogleSheetID:       0,
+			QuotaProject:        "billing-project",
+		},
+	}, {
+		name: "bare spreadsheet id with sheet id and name",
+		config: map[string]string{
+			KeyTokensFile:      validCredFile,
+			KeyCredentialsFile: validCredFile,
+			KeySheetURL:        "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+			KeySheetID:         "158080911",
+			KeySheetName:       "Sheet1",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:            AuthModeOAuth,
+			TokensFile:          validCredFile,
+			GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+			GoogleSheetID:       158080911,
+			GoogleSheetName:     "Sheet1",
+		},
+	}, {
+		name: "sheet id not matching the gid in sheets url",
+		config: map[string]string{
+			KeyTokensFile:      validCredFile,
+			KeyCredentialsFile: validCredFile,
+			KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+			KeySheetID:         "0",
+		},
+		err:  fmt.Errorf(`"sheetID" config value(0) doesn't match the gid(158080911) in "sheetsURL"`),
+		want: Config{},
+	}, {
+		name: "list of spreadsheets",
+		config: map[string]string{
+			KeyTokensFile:      validCredFile,
+			KeyCredentialsFile: validCredFile,
+			KeySheetURL:        "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4, https://docs.google.com/spreadsheets/d/2aBCdEfGhIjKlMnOpQrStUvWxYz/edit#gid=158080911,",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:            AuthModeOAuth,
+			TokensFile:          validCredFile,
+			GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+			GoogleSheetID:       NoSheetID,
+			ExtraSpreadsheets:   []SpreadsheetLocator{{SpreadsheetID: "2aBCdEfGhIjKlMnOpQrStUvWxYz", SheetID: 158080911}},
+		},
+	}, {
+		name: "spreadsheet listed twice",
+		config: map[string]string{
+			KeyTokensFile:      validCredFile,
+			KeyCredentialsFile: validCredFile,
+			KeySheetURL:        "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4,https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit",
+		},
+		err:  fmt.Errorf(`spreadsheet(19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4) is listed more than once in "sheetsURL"`),
+		want: Config{},
+	}, {
+		name: "drive folder url",
+		config: map[string]string{
+			KeyTokensFile:      validCredFile,
+			KeyCredentialsFile: validCredFile,
+			KeyDriveFolderID:   "https://drive.google.com/drive/folders/1AbCdEfGhIjKlMnOpQrStUvWxYz?usp=sharing",
+			KeySheetName:       "Expenses",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:        AuthModeOAuth,
+			TokensFile:      validCredFile,
+			Scopes:          []string{ScopeDriveMetadataReadOnly},
+			DriveFolderID:   "1AbCdEfGhIjKlMnOpQrStUvWxYz",
+			GoogleSheetID:   NoSheetID,
+			GoogleSheetName: "Expenses",
+		},
+	}, {
+		name: "bare drive folder id with sheet id",
+		config: map[string]string{
+			KeyTokensFile:      validCredFile,
+			KeyCredentialsFile: validCredFile,
+			KeyDriveFolderID:   "1AbCdEfGhIjKlMnOpQrStUvWxYz",
+			KeySheetID:         "0",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:      AuthModeOAuth,
+			TokensFile:    validCredFile,
+			Scopes:        []string{ScopeDriveMetadataReadOnly},
+			DriveFolderID: "1AbCdEfGhIjKlMnOpQrStUvWxYz",
+			GoogleSheetID: 0,
+		},
+	}, {
+		name: "drive folder with sheets url",
+		config: map[string]string{
+			KeyTokensFile:      validCredFile,
+			KeyCredentialsFile: validCredFile,
+			KeyDriveFolderID:   "1AbCdEfGhIjKlMnOpQrStUvWxYz",
+			KeySheetURL:        "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+		},
+		err:  fmt.Errorf(`"driveFolderID" config value can't be used with "sheetsURL"`),
+		want: Config{},
+	}, {
+		name: "invalid drive folder url",
+		config: map[string]string{
+			KeyTokensFile:      validCredFile,
+			KeyCredentialsFile: validCredFile,
+			KeyDriveFolderID:   "https://drive.google.com/file/d/1AbCdEfGhIjKlMnOpQrStUvWxYz/view",
+		},
+		err:  fmt.Errorf(`invalid "driveFolderID" config value, should be a Google Drive folder URL or a folder ID`),
+		want: Config{},
+	}, {
+		name: "invalid sheets url",
+		config: map[string]string{
+			KeyTokensFile:      validCredFile,
+			KeyCredentialsFile: validCredFile,
+			KeySheetURL:        "https://docs.google.com/document/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit",
 		},
-		err:  fmt.Errorf("invalid url passed, should match regex: \\/spreadsheets\\/d\\/([a-zA-Z0-9-_]+)\\/(.*)#gid=([0-9]+)"),
+		err:  fmt.Errorf(`invalid "sheetsURL" config value, should be a Google Sheets URL or a spreadsheet ID`),
 		want: Config{},
 	}, {
 		name: "invalid file",
@@ -84,6 +333,56 @@ func TestParse(t *testing.T) {
 		},
 		err:  fmt.Errorf("unable to read tokens file: open invalid_file.json: no such file or directory"),
 		want: Config{},
+	}, {
+		name: "inline credentials and tokens",
+		config: map[string]string{
+			KeyCredentialsJSON: string(credJSON),
+			KeyTokensJSON:      string(tokenJSON),
+			KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:            AuthModeOAuth,
+			GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+			GoogleSheetID:       158080911,
+		},
+	}, {
+		name: "inline credentials and tokens from env",
+		config: map[string]string{
+			KeyCredentialsJSON: "${CONDUIT_TEST_GOOGLE_CREDENTIAL_JSON}",
+			KeyTokensJSON:      "${CONDUIT_TEST_GOOGLE_TOKEN_JSON}",
+			KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+		},
+		err: nil,
+		want: Config{
+			AuthMode:            AuthModeOAuth,
+			GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+			GoogleSheetID:       158080911,
+		},
+	}, {
+		name: "inline credentials from unset env",
+		config: map[string]string{
+			KeyCredentialsJSON: "${CONDUIT_TEST_UNSET_ENV}",
+		},
+		err:  fmt.Errorf(`"credentialsJSON" config value references env variable "CONDUIT_TEST_UNSET_ENV", which is not set`),
+		want: Config{},
+	}, {
+		name: "credentials file and inline credentials",
+		config: map[string]string{
+			KeyCredentialsFile: validCredFile,
+			KeyCredentialsJSON: string(credJSON),
+		},
+		err:  fmt.Errorf(`"credentialsFile" and "credentialsJSON" config values are mutually exclusive`),
+		want: Config{},
+	}, {
+		name: "tokens file and inline tokens",
+		config: map[string]string{
+			KeyCredentialsFile: validCredFile,
+			KeyTokensFile:      validCredFile,
+			KeyTokensJSON:      string(tokenJSON),
+		},
+		err:  fmt.Errorf(`"tokensFile" and "tokensJSON" config values are mutually exclusive`),
+		want: Config{},
 	}, {
 		name: "invalid creds",
 		config: map[string]string{
@@ -104,12 +403,57 @@ func TestParse(t *testing.T) {
 				assert.NoError(t, err)
 				tt.want.OAuthConfig = cfg.OAuthConfig
 				tt.want.OAuthToken = cfg.OAuthToken
+				tt.want.JWTConfig = cfg.JWTConfig
+				tt.want.ExternalAccountConfig = cfg.ExternalAccountConfig
 				assert.Equal(t, tt.want, cfg)
+				if subject := tt.config[KeyImpersonateSubject]; subject != "" {
+					assert.Equal(t, subject, cfg.JWTConfig.Subject)
+				}
 			}
 		})
 	}
 }
 
+func TestConfig_TokenSource_ExternalAccount(t *testing.T) {
+	filePath := getFilePath("conduit-connector-google-sheets")
+	cfg, err := Parse(map[string]string{
+		KeyAuthMode:        "applicationDefault",
+		KeyCredentialsFile: fmt.Sprintf("%s/testdata/dummy_external_account.json", filePath),
+		KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, cfg.ExternalAccountConfig)
+
+	ts, err := cfg.TokenSource(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, ts)
+}
+
+func TestConfig_TokenSource_InvalidDelegation(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"unauthorized_client","error_description":"Client is unauthorized to retrieve access tokens using this method."}`))
+	}))
+	defer testServer.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	cfg := Config{
+		AuthMode: AuthModeServiceAccount,
+		JWTConfig: &jwt.Config{
+			Email:      "conduit@sheets1-316.iam.gserviceaccount.com",
+			PrivateKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+			Subject:    "user@example.com",
+			TokenURL:   testServer.URL,
+		},
+	}
+
+	ts, err := cfg.TokenSource(context.Background())
+	assert.Nil(t, ts)
+	assert.ErrorContains(t, err, `invalid "impersonateSubject" config: service account is not allowed to impersonate "user@example.com"`)
+}
+
 func getFilePath(path string) string {
 	wd, _ := os.Getwd()
 	for !strings.HasSuffix(wd, path) {
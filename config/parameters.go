@@ -0,0 +1,70 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	cconfig "github.com/conduitio/conduit-commons/config"
+)
+
+// ClientParameters returns the parameters of the authentication and of the HTTP client of the Google API requests,
+// shared by the source and the destination connectors
+func ClientParameters() cconfig.Parameters {
+	return map[string]cconfig.Parameter{
+		KeyAuthMode: {
+			Default:     "",
+			Description: "Authentication mode, detected from the credentials file when empty. Valid values: oauth, serviceAccount, applicationDefault",
+		},
+		KeyCredentialsFile: {
+			Default:     "",
+			Description: "path to credentials.json file used, either an OAuth client or a service account key. Optional workload identity federation config in applicationDefault auth mode",
+		},
+		KeyTokensFile: {
+			Default:     "",
+			Description: "path to token.json file containing a json with at least refresh_token, not needed for service account keys.",
+		},
+		KeyCredentialsJSON: {
+			Default:     "",
+			Description: "credentials.json content, or a ${ENV_NAME} reference to an env variable holding it, mutually exclusive with credentialsFile",
+		},
+		KeyTokensJSON: {
+			Default:     "",
+			Description: "token.json content, or a ${ENV_NAME} reference to an env variable holding it, mutually exclusive with tokensFile",
+		},
+		KeyImpersonateSubject: {
+			Default:     "",
+			Description: "Email of the user to impersonate using domain-wide delegation, only valid for service account keys.",
+		},
+		KeyEndpoint: {
+			Default:     "",
+			Description: "Base URL of the Sheets API, e.g. a local emulator. Default: the Google Sheets API",
+		},
+		KeyProxyURL: {
+			Default:     "",
+			Description: "HTTP proxy URL for the Google API requests. Default: HTTPS_PROXY/HTTP_PROXY env variables",
+		},
+		KeyRequestTimeout: {
+			Default:     "",
+			Description: "Timeout of a single Google API request, e.g. 30s. Default: no timeout",
+		},
+		KeyCACertFile: {
+			Default:     "",
+			Description: "path to a PEM encoded CA bundle trusted in addition to the system roots",
+		},
+		KeyQuotaProject: {
+			Default:     "",
+			Description: "GCP project the Sheets API quota and billing are attributed to, sent as the x-goog-user-project header",
+		},
+	}
+}
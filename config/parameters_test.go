@@ -0,0 +1,35 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientParameters(t *testing.T) {
+	params := ClientParameters()
+	for _, key := range []string{
+		KeyAuthMode, KeyCredentialsFile, KeyTokensFile, KeyCredentialsJSON, KeyTokensJSON, KeyImpersonateSubject,
+		KeyEndpoint, KeyProxyURL, KeyRequestTimeout, KeyCACertFile, KeyQuotaProject,
+	} {
+		assert.Contains(t, params, key)
+	}
+
+	// each connector adds its own parameters to a new map
+	params[KeySheetURL] = params[KeyEndpoint]
+	assert.NotContains(t, ClientParameters(), KeySheetURL)
+}
@@ -0,0 +1,151 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"golang.org/x/oauth2"
+)
+
+// tokenInfoURL is the Google endpoint returning the scopes granted to an access token
+var tokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+
+// googleTokenHosts are the hosts of the Google OAuth token endpoints, found in the token_uri of the client secret files
+var googleTokenHosts = map[string]bool{
+	"oauth2.googleapis.com": true,
+	"accounts.google.com":   true,
+	"www.googleapis.com":    true,
+}
+
+// impliedScopes lists, for a required scope, the broader scopes also granting it
+var impliedScopes = map[string][]string{
+	ScopeSpreadsheetsReadOnly: {ScopeSpreadsheets},
+	ScopeDriveMetadataReadOnly: {
+		"https://www.googleapis.com/auth/drive.metadata",
+		"https://www.googleapis.com/auth/drive.readonly",
+		"https://www.googleapis.com/auth/drive",
+	},
+}
+
+// validateTokenScopes checks the token carries all the required scopes. The granted scopes are read from the
+// token refresh response, or fetched from the tokeninfo endpoint for a token loaded as is from the tokens file.
+// A token issued by a token endpoint other than Google's, e.g. an emulator, isn't known to the tokeninfo endpoint,
+// the check of a token without refresh scopes being skipped with a warning
+func validateTokenScopes(ctx context.Context, ts oauth2.TokenSource, required []string, tokenURL string) error {
+	if len(required) == 0 {
+		return nil
+	}
+
+	token, err := ts.Token()
+	if err != nil {
+		return fmt.Errorf("unable to get OAuth token: %w", err)
+	}
+
+	granted, _ := token.Extra("scope").(string)
+	if granted == "" {
+		if !isGoogleTokenURL(tokenURL) {
+			sdk.Logger(ctx).Warn().Str("token_url", tokenURL).
+				Msg("OAuth token scopes not validated, the token isn't issued by the Google token endpoint")
+			return nil
+		}
+		granted, err = fetchTokenScopes(ctx, token.AccessToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	grantedSet := make(map[string]struct{})
+	for _, scope := range strings.Fields(granted) {
+		grantedSet[scope] = struct{}{}
+	}
+
+	var missing []string
+	for _, scope := range required {
+		if !hasScope(grantedSet, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf(
+			"OAuth token is missing the required scope(s) [%s], granted scope(s): [%s]. "+
+				"Generate a new token granting the required scope(s) using google-token-gen",
+			strings.Join(missing, ", "), granted,
+		)
+	}
+	return nil
+}
+
+// isGoogleTokenURL returns whether the token endpoint is Google's, an empty URL being Google's default endpoint
+func isGoogleTokenURL(tokenURL string) bool {
+	if tokenURL == "" {
+		return true
+	}
+	u, err := url.Parse(tokenURL)
+	return err == nil && u.Scheme == "https" && googleTokenHosts[u.Hostname()]
+}
+
+func hasScope(granted map[string]struct{}, scope string) bool {
+	if _, ok := granted[scope]; ok {
+		return true
+	}
+	for _, implied := range impliedScopes[scope] {
+		if _, ok := granted[implied]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchTokenScopes returns the space separated scopes granted to the access token
+func fetchTokenScopes(ctx context.Context, accessToken string) (string, error) {
+	// the token is sent in the request body, a query parameter could be recorded by proxy and access logs
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenInfoURL,
+		strings.NewReader(url.Values{"access_token": {accessToken}}.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("error creating tokeninfo request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// use the client of the token requests when set, honoring the configured proxy and CA bundle
+	client := http.DefaultClient
+	if ctxClient, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok && ctxClient != nil {
+		client = ctxClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch OAuth token scopes: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to fetch OAuth token scopes, tokeninfo returned HTTP status %d", resp.StatusCode)
+	}
+
+	var tokenInfo struct {
+		Scope string `json:"scope"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenInfo); err != nil {
+		return "", fmt.Errorf("error decoding tokeninfo response: %w", err)
+	}
+	return tokenInfo.Scope, nil
+}
@@ -0,0 +1,105 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestValidateTokenScopes(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Empty(t, r.URL.RawQuery)
+		assert.Equal(t, "dummy_access_token", r.PostFormValue("access_token"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"scope":"https://www.googleapis.com/auth/spreadsheets.readonly"}`))
+	}))
+	defer testServer.Close()
+	defaultTokenInfoURL := tokenInfoURL
+	tokenInfoURL = testServer.URL
+	defer func() { tokenInfoURL = defaultTokenInfoURL }()
+
+	token := &oauth2.Token{AccessToken: "dummy_access_token", Expiry: time.Now().Add(time.Hour)}
+	tests := []struct {
+		name     string
+		token    *oauth2.Token
+		required []string
+		tokenURL string
+		err      string
+	}{{
+		name:     "granted scope fetched from tokeninfo",
+		token:    token,
+		required: []string{ScopeSpreadsheetsReadOnly},
+	}, {
+		name:     "missing scope",
+		token:    token,
+		required: []string{ScopeSpreadsheets},
+		err: "OAuth token is missing the required scope(s) [https://www.googleapis.com/auth/spreadsheets], " +
+			"granted scope(s): [https://www.googleapis.com/auth/spreadsheets.readonly]. " +
+			"Generate a new token granting the required scope(s) using google-token-gen",
+	}, {
+		name: "read-only scope implied by refreshed token scope",
+		token: token.WithExtra(map[string]any{
+			"scope": "https://www.googleapis.com/auth/spreadsheets",
+		}),
+		required: []string{ScopeSpreadsheetsReadOnly},
+	}, {
+		name: "drive metadata scope implied by drive read-only scope",
+		token: token.WithExtra(map[string]any{
+			"scope": "https://www.googleapis.com/auth/spreadsheets.readonly https://www.googleapis.com/auth/drive.readonly",
+		}),
+		required: []string{ScopeSpreadsheetsReadOnly, ScopeDriveMetadataReadOnly},
+	}, {
+		name:     "granted scope fetched from tokeninfo with the Google token endpoint",
+		token:    token,
+		required: []string{ScopeSpreadsheets},
+		tokenURL: "https://oauth2.googleapis.com/token",
+		err: "OAuth token is missing the required scope(s) [https://www.googleapis.com/auth/spreadsheets], " +
+			"granted scope(s): [https://www.googleapis.com/auth/spreadsheets.readonly]. " +
+			"Generate a new token granting the required scope(s) using google-token-gen",
+	}, {
+		name:     "tokeninfo skipped with a custom token endpoint",
+		token:    token,
+		required: []string{ScopeSpreadsheets},
+		tokenURL: "http://localhost:8080/token",
+	}, {
+		name: "refreshed token scope checked with a custom token endpoint",
+		token: token.WithExtra(map[string]any{
+			"scope": "https://www.googleapis.com/auth/spreadsheets.readonly",
+		}),
+		required: []string{ScopeSpreadsheets},
+		tokenURL: "http://localhost:8080/token",
+		err: "OAuth token is missing the required scope(s) [https://www.googleapis.com/auth/spreadsheets], " +
+			"granted scope(s): [https://www.googleapis.com/auth/spreadsheets.readonly]. " +
+			"Generate a new token granting the required scope(s) using google-token-gen",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTokenScopes(context.Background(), oauth2.StaticTokenSource(tt.token), tt.required, tt.tokenURL)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/conduitio-labs/conduit-connector-google-sheets/internal/fileutil"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"golang.org/x/oauth2"
+)
+
+var (
+	// tokenSources keeps one persistentTokenSource per tokens file, so the source and destination connectors
+	// using the same tokens file share the refreshed tokens instead of racing on a rotated refresh token.
+	// A token source is removed once released by all the connectors using it, see ReleaseTokenSource
+	tokenSources   = map[string]*persistentTokenSource{}
+	tokenSourcesMu sync.Mutex
+)
+
+// persistentTokenSource is an oauth2.TokenSource writing every refreshed token back to the tokens file,
+// so the refreshed access token and a rotated refresh token survive connector restarts
+type persistentTokenSource struct {
+	mu sync.Mutex
+	// ctx is the context of the connector which created the token source, detached from its cancellation,
+	// used to log the failed writes of the tokens file
+	ctx context.Context
+	// base is the token source refreshing the token using the OAuth client config
+	base oauth2.TokenSource
+	// clientID is the ID of the OAuth client the token was issued to
+	clientID string
+	// path of the tokens file to write the refreshed token to
+	path string
+	// last is the last token written to (or read from) the tokens file
+	last *oauth2.Token
+	// refs is the number of connectors using the token source, guarded by tokenSourcesMu
+	refs int
+}
+
+// sharedTokenSource returns the persistentTokenSource for the tokens file, creating it on first use, or when the
+// tokens file or the OAuth client changed since it was created, e.g. the tokens file being regenerated.
+// Tokens passed inline(without a tokens file) are only refreshed in memory
+func sharedTokenSource(ctx context.Context, path string, oauthCfg *oauth2.Config, token *oauth2.Token) (oauth2.TokenSource, error) {
+	if path == "" {
+		return oauthCfg.TokenSource(ctx, token), nil
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve tokens file path: %w", err)
+	}
+
+	// the tokens file may have been regenerated since the config was parsed, or since the token source was created
+	if fileToken, err := readTokenFile(absPath); err == nil {
+		token = fileToken
+	}
+
+	tokenSourcesMu.Lock()
+	defer tokenSourcesMu.Unlock()
+
+	if ts, ok := tokenSources[absPath]; ok && ts.matches(oauthCfg.ClientID, token) {
+		ts.refs++
+		return ts, nil
+	}
+	// the token source outlives the connector which created it, detach it from the context cancellation
+	ctx = context.WithoutCancel(ctx)
+	ts := &persistentTokenSource{
+		ctx:      ctx,
+		base:     oauthCfg.TokenSource(ctx, token),
+		clientID: oauthCfg.ClientID,
+		path:     absPath,
+		last:     token,
+		refs:     1,
+	}
+	tokenSources[absPath] = ts
+	return ts, nil
+}
+
+// ReleaseTokenSource releases the token source returned by Config.TokenSource, on the teardown of the connector.
+// The token source of a tokens file is removed from the shared token sources once released by all the connectors
+// using it, or right away if it was replaced, the tokens file being regenerated
+func ReleaseTokenSource(ts oauth2.TokenSource) {
+	p, ok := ts.(*persistentTokenSource)
+	if !ok {
+		return
+	}
+
+	tokenSourcesMu.Lock()
+	defer tokenSourcesMu.Unlock()
+
+	p.refs--
+	if p.refs <= 0 && tokenSources[p.path] == p {
+		delete(tokenSources, p.path)
+	}
+}
+
+// matches reports whether the token source was created for the OAuth client, and holds the token of the tokens file
+func (p *persistentTokenSource) matches(clientID string, token *oauth2.Token) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.clientID == clientID && p.last != nil &&
+		p.last.AccessToken == token.AccessToken && p.last.RefreshToken == token.RefreshToken
+}
+
+// readTokenFile reads the token of the tokens file
+func readTokenFile(path string) (*oauth2.Token, error) {
+	tokenBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var token oauth2.Token
+	if err := json.Unmarshal(tokenBytes, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Token returns a valid token, refreshing it if needed, and persists it if it changed since the last write.
+// A failed write is only logged, e.g. for a tokens file mounted read-only from a secret
+func (p *persistentTokenSource) Token() (*oauth2.Token, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	token, err := p.base.Token()
+	if err != nil {
+		return nil, err
+	}
+	if p.last != nil && p.last.AccessToken == token.AccessToken && p.last.RefreshToken == token.RefreshToken {
+		return token, nil
+	}
+	if err := writeTokenFile(p.path, token); err != nil {
+		// the write isn't retried until the token is refreshed again
+		sdk.Logger(p.ctx).Warn().Err(err).Str("tokens_file", p.path).Msg("unable to persist refreshed token")
+	}
+	p.last = token
+	return token, nil
+}
+
+// writeTokenFile atomically replaces the tokens file with the token, the file is only readable by the owner
+func writeTokenFile(path string, token *oauth2.Token) error {
+	tokenBytes, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("error marshaling token: %w", err)
+	}
+
+	if err := fileutil.WriteFileAtomic(path, tokenBytes); err != nil {
+		return fmt.Errorf("error replacing tokens file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestPersistentTokenSource_RefreshRotatesTokensFile(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"new_access_token","token_type":"Bearer","refresh_token":"rotated_refresh_token","expires_in":3600}`))
+	}))
+	defer testServer.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token.json")
+	expired := &oauth2.Token{
+		AccessToken:  "old_access_token",
+		TokenType:    "Bearer",
+		RefreshToken: "old_refresh_token",
+		Expiry:       time.Now().Add(-time.Hour),
+	}
+	assert.NoError(t, writeTokenFile(tokenFile, expired))
+
+	oauthCfg := &oauth2.Config{Endpoint: oauth2.Endpoint{TokenURL: testServer.URL}}
+	ts, err := sharedTokenSource(context.Background(), tokenFile, oauthCfg, expired)
+	assert.NoError(t, err)
+
+	token, err := ts.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "new_access_token", token.AccessToken)
+
+	info, err := os.Stat(tokenFile)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	tokenBytes, err := os.ReadFile(tokenFile)
+	assert.NoError(t, err)
+	var persisted oauth2.Token
+	assert.NoError(t, json.Unmarshal(tokenBytes, &persisted))
+	assert.Equal(t, "new_access_token", persisted.AccessToken)
+	assert.Equal(t, "rotated_refresh_token", persisted.RefreshToken)
+
+	// the same tokens file shares the token source
+	shared, err := sharedTokenSource(context.Background(), tokenFile, oauthCfg, expired)
+	assert.NoError(t, err)
+	assert.Same(t, ts, shared)
+}
+
+func TestSharedTokenSource_RegeneratedTokensFile(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token.json")
+	first := &oauth2.Token{
+		AccessToken:  "first_access_token",
+		TokenType:    "Bearer",
+		RefreshToken: "first_refresh_token",
+		Expiry:       time.Now().Add(time.Hour),
+	}
+	assert.NoError(t, writeTokenFile(tokenFile, first))
+
+	oauthCfg := &oauth2.Config{ClientID: "client"}
+	ts, err := sharedTokenSource(context.Background(), tokenFile, oauthCfg, first)
+	assert.NoError(t, err)
+	token, err := ts.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "first_access_token", token.AccessToken)
+
+	// the tokens file is regenerated, e.g. with more scopes, before the connector is opened again
+	second := &oauth2.Token{
+		AccessToken:  "second_access_token",
+		TokenType:    "Bearer",
+		RefreshToken: "second_refresh_token",
+		Expiry:       time.Now().Add(time.Hour),
+	}
+	assert.NoError(t, writeTokenFile(tokenFile, second))
+
+	regenerated, err := sharedTokenSource(context.Background(), tokenFile, oauthCfg, first)
+	assert.NoError(t, err)
+	assert.NotSame(t, ts, regenerated)
+	token, err = regenerated.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "second_access_token", token.AccessToken)
+
+	// another OAuth client doesn't share the token source
+	other, err := sharedTokenSource(context.Background(), tokenFile, &oauth2.Config{ClientID: "other"}, second)
+	assert.NoError(t, err)
+	assert.NotSame(t, regenerated, other)
+}
+
+func TestPersistentTokenSource_ReadOnlyTokensFile(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"new_access_token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer testServer.Close()
+
+	// the tokens file can't be replaced, like a tokens file mounted read-only from a secret
+	tokenFile := filepath.Join(t.TempDir(), "token.json")
+	assert.NoError(t, os.Mkdir(tokenFile, 0o700))
+	expired := &oauth2.Token{
+		AccessToken:  "old_access_token",
+		TokenType:    "Bearer",
+		RefreshToken: "refresh_token",
+		Expiry:       time.Now().Add(-time.Hour),
+	}
+	assert.Error(t, writeTokenFile(tokenFile, expired))
+
+	oauthCfg := &oauth2.Config{Endpoint: oauth2.Endpoint{TokenURL: testServer.URL}}
+	ts, err := sharedTokenSource(context.Background(), tokenFile, oauthCfg, expired)
+	assert.NoError(t, err)
+	defer ReleaseTokenSource(ts)
+
+	// the refreshed token is used, though it can't be written back
+	token, err := ts.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "new_access_token", token.AccessToken)
+}
+
+func TestReleaseTokenSource(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token.json")
+	token := &oauth2.Token{
+		AccessToken:  "access_token",
+		TokenType:    "Bearer",
+		RefreshToken: "refresh_token",
+		Expiry:       time.Now().Add(time.Hour),
+	}
+	assert.NoError(t, writeTokenFile(tokenFile, token))
+	absPath, err := filepath.Abs(tokenFile)
+	assert.NoError(t, err)
+
+	// the source and destination connectors share the token source
+	oauthCfg := &oauth2.Config{ClientID: "client"}
+	source, err := sharedTokenSource(context.Background(), tokenFile, oauthCfg, token)
+	assert.NoError(t, err)
+	destination, err := sharedTokenSource(context.Background(), tokenFile, oauthCfg, token)
+	assert.NoError(t, err)
+	assert.Same(t, source, destination)
+
+	ReleaseTokenSource(source)
+	tokenSourcesMu.Lock()
+	assert.Contains(t, tokenSources, absPath)
+	tokenSourcesMu.Unlock()
+
+	ReleaseTokenSource(destination)
+	tokenSourcesMu.Lock()
+	assert.NotContains(t, tokenSources, absPath)
+	tokenSourcesMu.Unlock()
+}
@@ -0,0 +1,73 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// parseTransport parses the endpoint, proxy, request timeout and CA bundle configs into cfg
+func parseTransport(config map[string]string, cfg *Config) error {
+	if endpoint := strings.TrimSpace(config[KeyEndpoint]); endpoint != "" {
+		if _, err := parseHTTPURL(KeyEndpoint, endpoint); err != nil {
+			return err
+		}
+		cfg.Endpoint = endpoint
+	}
+
+	if proxy := strings.TrimSpace(config[KeyProxyURL]); proxy != "" {
+		proxyURL, err := parseHTTPURL(KeyProxyURL, proxy)
+		if err != nil {
+			return err
+		}
+		cfg.ProxyURL = proxyURL
+	}
+
+	if timeout := strings.TrimSpace(config[KeyRequestTimeout]); timeout != "" {
+		requestTimeout, err := time.ParseDuration(timeout)
+		if err != nil || requestTimeout <= 0 {
+			return fmt.Errorf("%q config value should be a positive duration, e.g. 30s", KeyRequestTimeout)
+		}
+		cfg.RequestTimeout = requestTimeout
+	}
+
+	if caFile := strings.TrimSpace(config[KeyCACertFile]); caFile != "" {
+		caBundle, err := os.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("unable to read CA cert file: %w", err)
+		}
+		// validate the bundle here, so an invalid file fails on Configure instead of Open
+		if !x509.NewCertPool().AppendCertsFromPEM(caBundle) {
+			return fmt.Errorf("no PEM encoded certificates found in the %q config value", KeyCACertFile)
+		}
+		cfg.CABundle = caBundle
+	}
+
+	return nil
+}
+
+// parseHTTPURL parses an absolute http(s) URL config value
+func parseHTTPURL(name, value string) (*url.URL, error) {
+	parsedURL, err := url.Parse(value)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return nil, fmt.Errorf("invalid %q config value, should be an absolute http(s) URL", name)
+	}
+	return parsedURL, nil
+}
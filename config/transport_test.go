@@ -0,0 +1,88 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTransport(t *testing.T) {
+	filePath := getFilePath("conduit-connector-google-sheets")
+
+	tests := []struct {
+		name   string
+		config map[string]string
+		want   Config
+		err    error
+	}{{
+		name:   "defaults",
+		config: map[string]string{},
+		want:   Config{},
+	}, {
+		name: "endpoint, proxy and timeout",
+		config: map[string]string{
+			KeyEndpoint:       "http://localhost:8080/",
+			KeyProxyURL:       "http://proxy.internal:3128",
+			KeyRequestTimeout: "30s",
+		},
+		want: Config{
+			Endpoint:       "http://localhost:8080/",
+			ProxyURL:       &url.URL{Scheme: "http", Host: "proxy.internal:3128"},
+			RequestTimeout: 30 * time.Second,
+		},
+	}, {
+		name:   "invalid endpoint",
+		config: map[string]string{KeyEndpoint: "localhost:8080"},
+		err:    fmt.Errorf(`invalid "endpoint" config value, should be an absolute http(s) URL`),
+	}, {
+		name:   "invalid proxy",
+		config: map[string]string{KeyProxyURL: "socks://proxy"},
+		err:    fmt.Errorf(`invalid "proxyURL" config value, should be an absolute http(s) URL`),
+	}, {
+		name:   "invalid timeout",
+		config: map[string]string{KeyRequestTimeout: "-1s"},
+		err:    fmt.Errorf(`"requestTimeout" config value should be a positive duration, e.g. 30s`),
+	}, {
+		name:   "CA bundle without certificates",
+		config: map[string]string{KeyCACertFile: fmt.Sprintf("%s/testdata/dummy_cred.json", filePath)},
+		err:    fmt.Errorf(`no PEM encoded certificates found in the "caCertFile" config value`),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Config
+			err := parseTransport(tt.config, &got)
+			if tt.err != nil {
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTransport_CABundle(t *testing.T) {
+	filePath := getFilePath("conduit-connector-google-sheets")
+
+	var got Config
+	err := parseTransport(map[string]string{KeyCACertFile: fmt.Sprintf("%s/testdata/dummy_ca.pem", filePath)}, &got)
+	assert.NoError(t, err)
+	assert.Contains(t, string(got.CABundle), "BEGIN CERTIFICATE")
+}
@@ -22,9 +22,6 @@ import (
 )
 
 const (
-	// KeySheetName is the name of the sheet needed to fetch data.
-	KeySheetName = "sheetName"
-
 	// KeyValueInputOption is the config name for how the input data
 	// should be inserted.
 	KeyValueInputOption = "valueInputOption"
@@ -42,7 +39,6 @@ const (
 // Config represents destination configuration with Google-Sheet configurations
 type Config struct {
 	config.Config
-	SheetName string
 	// How the data is to be interpreted by the Google sheets
 	// In case of USER_ENTERED, the data is inserted similar to data insertion from browser
 	// In RAW, the data is inserted without any parsing
@@ -52,14 +48,15 @@ type Config struct {
 
 // Parse attempts to parse the configurations into a Config struct that Destination could utilize
 func Parse(cfg map[string]string) (Config, error) {
-	sharedConfig, err := config.Parse(cfg)
+	sharedConfig, err := config.Parse(cfg, config.ScopeSpreadsheets)
 	if err != nil {
 		return Config{}, fmt.Errorf("error parsing shared config, %w", err)
 	}
-
-	sheetName := cfg[KeySheetName]
-	if sheetName == "" {
-		return Config{}, requiredConfigErr(KeySheetName)
+	if sharedConfig.DriveFolderID != "" {
+		return Config{}, fmt.Errorf("%q config value is only supported by the source", config.KeyDriveFolderID)
+	}
+	if len(sharedConfig.ExtraSpreadsheets) > 0 {
+		return Config{}, fmt.Errorf("%q config value should hold a single spreadsheet", config.KeySheetURL)
 	}
 
 	sheetValueOption := cfg[KeyValueInputOption]
@@ -88,14 +85,9 @@ func Parse(cfg map[string]string) (Config, error) {
 
 	destinationConfig := Config{
 		Config:           sharedConfig,
-		SheetName:        sheetName,
 		ValueInputOption: sheetValueOption,
 		MaxRetries:       retries,
 	}
 
 	return destinationConfig, nil
 }
-
-func requiredConfigErr(name string) error {
-	return fmt.Errorf("%q config value must be set", name)
-}
@@ -40,7 +40,7 @@ func TestParse(t *testing.T) {
 		{
 			testCase: "Checking against default values",
 			params:   map[string]string{},
-			err:      fmt.Errorf("error parsing shared config, \"credentialsFile\" config value must be set"),
+			err:      fmt.Errorf("error parsing shared config, \"credentialsFile\" or \"credentialsJSON\" config value must be set"),
 			expected: Config{},
 		},
 		{
@@ -49,22 +49,52 @@ func TestParse(t *testing.T) {
 				config.KeyTokensFile:      validCredFile,
 				config.KeyCredentialsFile: validCredFile,
 				config.KeySheetURL:        "",
-				KeySheetName:              "Sheet",
+				config.KeySheetName:       "Sheet",
 				KeyValueInputOption:       "",
 			},
 			err:      fmt.Errorf("error parsing shared config, \"sheetsURL\" config value must be set"),
 			expected: Config{},
 		},
 		{
-			testCase: "Checking against random values case",
+			testCase: "Checking against missing sheet name",
 			params: map[string]string{
 				config.KeyTokensFile:      validCredFile,
 				config.KeyCredentialsFile: validCredFile,
 				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
-				KeySheetName:              "",
+				config.KeySheetName:       "",
 				KeyValueInputOption:       "",
 			},
-			err:      fmt.Errorf("\"sheetName\" config value must be set"),
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheets},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+				},
+				ValueInputOption: defaultValueInputOption,
+				MaxRetries:       3,
+			},
+		},
+		{
+			testCase: "Checking for a list of spreadsheets",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4,2aBCdEfGhIjKlMnOpQrStUvWxYz",
+			},
+			err:      fmt.Errorf("\"sheetsURL\" config value should hold a single spreadsheet"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking for a drive folder",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeyDriveFolderID:   "1AbCdEfGhIjKlMnOpQrStUvWxYz",
+			},
+			err:      fmt.Errorf("\"driveFolderID\" config value is only supported by the source"),
 			expected: Config{},
 		},
 		{
@@ -73,16 +103,19 @@ func TestParse(t *testing.T) {
 				config.KeyTokensFile:      validCredFile,
 				config.KeyCredentialsFile: validCredFile,
 				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
-				KeySheetName:              "Sheet",
+				config.KeySheetName:       "Sheet",
 				KeyValueInputOption:       "",
 			},
 			err: nil,
 			expected: Config{
 				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheets},
 					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
 					GoogleSheetID:       158080911,
+					GoogleSheetName:     "Sheet",
 				},
-				SheetName:        "Sheet",
 				ValueInputOption: defaultValueInputOption,
 				MaxRetries:       3,
 			},
@@ -93,17 +126,20 @@ func TestParse(t *testing.T) {
 				config.KeyTokensFile:      validCredFile,
 				config.KeyCredentialsFile: validCredFile,
 				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
-				KeySheetName:              "Sheet",
+				config.KeySheetName:       "Sheet",
 				KeyValueInputOption:       "RAW",
 				KeyMaxRetries:             "5",
 			},
 			err: nil,
 			expected: Config{
 				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheets},
 					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
 					GoogleSheetID:       158080911,
+					GoogleSheetName:     "Sheet",
 				},
-				SheetName:        "Sheet",
 				ValueInputOption: "RAW",
 				MaxRetries:       5,
 			},
@@ -114,16 +150,19 @@ func TestParse(t *testing.T) {
 				config.KeyTokensFile:      validCredFile,
 				config.KeyCredentialsFile: validCredFile,
 				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
-				KeySheetName:              "Sheet",
+				config.KeySheetName:       "Sheet",
 				KeyValueInputOption:       "USER_ENTERED",
 			},
 			err: nil,
 			expected: Config{
 				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheets},
 					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
 					GoogleSheetID:       158080911,
+					GoogleSheetName:     "Sheet",
 				},
-				SheetName:        "Sheet",
 				ValueInputOption: defaultValueInputOption,
 				MaxRetries:       3,
 			},
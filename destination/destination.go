@@ -17,12 +17,14 @@ package destination
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	"github.com/conduitio-labs/conduit-connector-google-sheets/config"
 	"github.com/conduitio-labs/conduit-connector-google-sheets/sheets"
 	cconfig "github.com/conduitio/conduit-commons/config"
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
+	"golang.org/x/oauth2"
 )
 
 // Destination connector
@@ -33,6 +35,8 @@ type Destination struct {
 	config Config
 	// writer is the instance of sheets writer, which is a wrapper over sheets write API
 	writer *sheets.Writer
+	// tokenSource is the token source of the API requests, released on teardown
+	tokenSource oauth2.TokenSource
 }
 
 func NewDestination() sdk.Destination {
@@ -41,26 +45,20 @@ func NewDestination() sdk.Destination {
 
 // Parameters returns a map of named config.Parameters that describe how to configure the Destination.
 func (d *Destination) Parameters() cconfig.Parameters {
-	return map[string]cconfig.Parameter{
-		config.KeyCredentialsFile: {
+	params := config.ClientParameters()
+	maps.Copy(params, map[string]cconfig.Parameter{
+		config.KeySheetURL: {
 			Default:     "",
-			Description: "path to credentials.json file used",
+			Description: "Google sheet url, or the spreadsheet ID, to write the records to",
 			Validations: []cconfig.Validation{cconfig.ValidationRequired{}},
 		},
-		config.KeyTokensFile: {
+		config.KeySheetID: {
 			Default:     "",
-			Description: "path to token.json file containing a json with at least refresh_token.",
-			Validations: []cconfig.Validation{cconfig.ValidationRequired{}},
+			Description: "gid of the sheet to write the records to, if not set in the sheet url",
 		},
-		config.KeySheetURL: {
+		config.KeySheetName: {
 			Default:     "",
-			Description: "Google sheet url to fetch the records from",
-			Validations: []cconfig.Validation{cconfig.ValidationRequired{}},
-		},
-		KeySheetName: {
-			Default:     "",
-			Description: "Google sheet name to fetch the records",
-			Validations: []cconfig.Validation{cconfig.ValidationRequired{}},
+			Description: "Google sheet name to write the records to, must match the gid if both are set. Default: the sheet of the gid, or the first sheet",
 		},
 		KeyValueInputOption: {
 			Default:     "USER_ENTERED",
@@ -70,7 +68,8 @@ func (d *Destination) Parameters() cconfig.Parameters {
 			Default:     "3",
 			Description: "Max API retries to be attempted, in case of 429 error, before returning error",
 		},
-	}
+	})
+	return params
 }
 
 // Configure parses and initializes the config.
@@ -82,7 +81,6 @@ func (d *Destination) Configure(_ context.Context, cfg cconfig.Config) error {
 
 	d.config = Config{
 		Config:           sheetsConfig.Config,
-		SheetName:        sheetsConfig.SheetName,
 		ValueInputOption: sheetsConfig.ValueInputOption,
 	}
 	return nil
@@ -90,15 +88,20 @@ func (d *Destination) Configure(_ context.Context, cfg cconfig.Config) error {
 
 // Open makes sure everything is prepared to receive records.
 func (d *Destination) Open(ctx context.Context) error {
-	writer, err := sheets.NewWriter(
-		ctx,
-		d.config.OAuthConfig,
-		d.config.OAuthToken,
-		d.config.GoogleSpreadsheetID,
-		d.config.SheetName,
-		d.config.ValueInputOption,
-		d.config.MaxRetries,
-	)
+	clientArgs, err := sheets.NewClientArgs(ctx, d.config.Config)
+	if err != nil {
+		return err
+	}
+	d.tokenSource = clientArgs.TokenSource
+
+	writer, err := sheets.NewWriter(ctx, sheets.WriterArgs{
+		ClientArgs:       clientArgs,
+		SpreadsheetID:    d.config.GoogleSpreadsheetID,
+		SheetID:          d.config.GoogleSheetID,
+		SheetName:        d.config.GoogleSheetName,
+		ValueInputOption: d.config.ValueInputOption,
+		MaxRetries:       d.config.MaxRetries,
+	})
 	if err != nil {
 		return fmt.Errorf("unable to init writer: %w", err)
 	}
@@ -121,6 +124,10 @@ func (d *Destination) Write(ctx context.Context, records []opencdc.Record) (int,
 // Teardown writes all the pending records to sheets and gracefully disconnects the client
 func (d *Destination) Teardown(_ context.Context) error {
 	d.writer = nil
+	if d.tokenSource != nil {
+		config.ReleaseTokenSource(d.tokenSource)
+		d.tokenSource = nil
+	}
 
 	return nil
 }
@@ -17,35 +17,58 @@ package sheets
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/conduitio-labs/conduit-connector-google-sheets/source/position"
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/state"
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
-	"golang.org/x/oauth2"
 	"google.golang.org/api/googleapi"
-	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
 )
 
 const majorDimension = "ROWS"
 
+// BatchReader reads a sheet(tab) for the MultiReader, building the data filters of the sheet rows,
+// and converting the fetched rows to records
 type BatchReader struct {
 	// spreadsheet ID of the Google sheet
 	spreadsheetID string
 	// gid of the sheet extracted from the sheet URL <url>#gid=<gid>
 	sheetID int64
+	// titles of the spreadsheet and the sheet, added to the records metadata
+	spreadsheetTitle string
+	sheetTitle       string
+	// driveFile is the Drive file of the spreadsheet read from a Drive folder, nil otherwise
+	driveFile *DriveFile
+	// cellRange is the range of the cells read in the sheet, the row offsets being the sheet row numbers
+	cellRange CellRange
+	// namedRange is the named range read instead of the cellRange, fetched as a whole on each poll
+	// so the range is followed wherever it's moved, the row offsets being the row numbers in the range
+	namedRange string
+	// rangeStartRow and rangeStartColumn are the 0-based sheet indices of the first cell of the namedRange
+	// as of the last fetch, added to the row numbers and the columns of the records metadata
+	rangeStartRow    int64
+	rangeStartColumn int64
+	// batchSize is the maximum number of rows fetched per request, 0 to fetch all the rows from the offset
+	batchSize int64
+	// rowCount is the number of rows of the sheet grid as of the reader creation or the last sheets discovery
+	rowCount int64
+	// pageEnd is the 0-based index of the row after the page of batchSize rows fetched by the last request,
+	// the start of the next page, 0 unless the page was truncated or empty. The next page starts after the empty pages,
+	// which don't move the row offset, the rows after the offset being fetched again once the pages are all read.
+	pageEnd int64
+	// emptyFrom is the 0-based index of the first row of the empty pages walked by the last requests, and emptyTail
+	// is set once the rows from emptyFrom are found empty up to the end of the sheet grid, or of the range.
+	// The empty pages of the tail aren't walked again, the polls fetching the rows after the offset.
+	emptyFrom int64
+	emptyTail bool
 	// instance of sheets service, used to interact with Google Sheets APIs
 	sheetSvc *sheets.Service
-	// If rate limit is exceeded, nextRun is used to skip hitting API till the specified time.
-	// Exponential backoff is used to decide nextRun time on rate-limit error, uses retryCount*pollingPeriod seconds as duration
-	nextRun time.Time
-	// polling period defined in the config, it is used to implement exponential backoff
-	pollingPeriod time.Duration
-	// the count of unsuccessful retries made after getting 429(rate-limit exceeded) http error status.
-	retryCount int64
 	// dateTimeRenderOption Determines how dates, times, and durations in the response should be rendered.
 	// This is ignored if responseValueRenderOption is FORMATTED_VALUE.
 	// The default dateTime render option is FORMATTED_STRING for the connector.
@@ -53,80 +76,451 @@ type BatchReader struct {
 	// valueRenderOption Determines how values in the response should be rendered.
 	// The default render option is FORMATTED_VALUE.
 	valueRenderOption string
+	// headerRow is the 1-based row number of the header row providing the field names of the records,
+	// 0 if there is no header row, in which case the records hold the row as a JSON array
+	headerRow int64
+	// typedValues converts the cell values to int64, float64, bool or time.Time, using the cells' effective value and format
+	typedValues bool
+	// location is the spreadsheet time zone, the location of the date/time values converted with typedValues
+	location *time.Location
+	// detectUpdates enables the full scan of the sheet on each poll, to emit the changes of the already read rows,
+	// compared to the last known content of the rows in rowStates
+	detectUpdates bool
+	// detectDeletes enables the full scan of the sheet on each poll, to emit the rows removed since the last poll,
+	// the rows being followed by the value of their identityColumn
+	detectDeletes  bool
+	identityColumn string
+	rowStates      map[int64]state.Row
+	// keyColumns are the header names or column letters of the columns holding the record keys,
+	// the row number is the record key when empty
+	keyColumns []string
+	// skipEmptyKeys skips the rows with blank key cells, and holdEmptyKeys holds the row offset before the first row
+	// with blank key cells, instead of failing
+	skipEmptyKeys bool
+	holdEmptyKeys bool
+	// createdAtColumn is the header name or the column letter of the column holding the records created-at time,
+	// parsed with the createdAtLayout in the createdAtLocation, the fetch time is used when empty
+	createdAtColumn   string
+	createdAtLayout   string
+	createdAtLocation *time.Location
+}
+
+// backoff skips the polls after a rate limit error, with exponential back off
+type backoff struct {
+	// If rate limit is exceeded, nextRun is used to skip hitting API till the specified time.
+	// Exponential backoff is used to decide nextRun time on rate-limit error, uses retryCount*pollingPeriod seconds as duration
+	nextRun time.Time
+	// polling period defined in the config, it is used to implement exponential backoff
+	pollingPeriod time.Duration
+	// the count of unsuccessful retries made after getting 429(rate-limit exceeded) http error status.
+	retryCount int64
 }
 
 type BatchReaderArgs struct {
-	OAuthConfig          *oauth2.Config
-	OAuthToken           *oauth2.Token
-	SpreadsheetID        string
+	ClientArgs
+	SpreadsheetID string
+	// SheetID is the gid of the sheet, negative if unknown, in which case it's resolved from the SheetName
+	// or the first sheet of the spreadsheet is used
 	SheetID              int64
+	SheetName            string
 	DateTimeRenderOption string
 	ValueRenderOption    string
 	PollingPeriod        time.Duration
+	// HeaderRow is the 1-based row number of the header row, 0 to disable the structured records
+	HeaderRow int64
+	// TypedValues enables the conversion of the cell values to their types, instead of the rendered values
+	TypedValues bool
+	// DetectUpdates enables emitting OperationUpdate records for the changed rows, up to the row offset
+	DetectUpdates bool
+	// DetectDeletes enables emitting OperationDelete records for the removed rows, requires the RowIdentityColumn
+	DetectDeletes bool
+	// RowIdentityColumn is the header name or the column letter of the column identifying the rows
+	RowIdentityColumn string
+	// KeyColumns are the header names or column letters of the columns holding the record keys
+	KeyColumns []string
+	// SkipEmptyKeys skips the rows with blank key cells, instead of returning an error
+	SkipEmptyKeys bool
+	// HoldEmptyKeys holds the row offset before the first row with blank key cells after the offset, instead of
+	// returning an error, the row and the rows after it being read again on the next poll
+	HoldEmptyKeys bool
+	// CreatedAtColumn is the header name or the column letter of the column holding the records created-at time
+	CreatedAtColumn string
+	// CreatedAtLayout is the time layout of the created-at cells, DefaultCreatedAtLayout if empty
+	CreatedAtLayout string
+	// CreatedAtLocation is the time zone of the created-at cells without zone, the spreadsheet time zone if nil
+	CreatedAtLocation *time.Location
+	// DriveFile is the Drive file of the spreadsheet when read from a Drive folder,
+	// its ID and name are added to the records metadata
+	DriveFile *DriveFile
+	// Range is the range of the cells read in the sheet, the whole sheet if zero, its SheetName is ignored
+	Range CellRange
+	// NamedRange is the name of the named range read instead of the Range, the sheet is then the one of the named range,
+	// and the HeaderRow and the row offsets are relative to the range
+	NamedRange string
+	// BatchSize is the maximum number of rows fetched per request, 0 to fetch all the rows from the offset,
+	// not supported along with DetectUpdates, DetectDeletes or NamedRange, which read all the rows
+	BatchSize int64
 }
 
-func NewBatchReader(ctx context.Context, args BatchReaderArgs) (*BatchReader, error) {
-	sheetService, err := sheets.NewService(ctx, option.WithHTTPClient(args.OAuthConfig.Client(ctx, args.OAuthToken)))
-	if err != nil {
-		return nil, fmt.Errorf("error creating sheets service client: %w", err)
+// newBatchReader creates the reader of the sheet, using the spreadsheet metadata, the sheet locator of the args is ignored
+func newBatchReader(
+	sheetService *sheets.Service,
+	spreadsheet *sheets.Spreadsheet,
+	sheetProperties *sheets.SheetProperties,
+	args BatchReaderArgs,
+) (*BatchReader, error) {
+	var spreadsheetTitle, spreadsheetTimeZone string
+	if spreadsheet.Properties != nil {
+		spreadsheetTitle = spreadsheet.Properties.Title
+		spreadsheetTimeZone = spreadsheet.Properties.TimeZone
+	}
+
+	var location *time.Location
+	if args.TypedValues {
+		var err error
+		if location, err = spreadsheetLocation(spreadsheetTimeZone); err != nil {
+			return nil, err
+		}
+	}
+
+	var createdAtLocation *time.Location
+	createdAtLayout := args.CreatedAtLayout
+	if args.CreatedAtColumn != "" {
+		var err error
+		createdAtLocation, err = resolveCreatedAtLocation(args.CreatedAtLocation, spreadsheetTimeZone)
+		if err != nil {
+			return nil, err
+		}
+		if createdAtLayout == "" {
+			createdAtLayout = DefaultCreatedAtLayout
+		}
 	}
+
 	return &BatchReader{
 		spreadsheetID:        args.SpreadsheetID,
-		sheetID:              args.SheetID,
-		pollingPeriod:        args.PollingPeriod,
+		sheetID:              sheetProperties.SheetId,
+		spreadsheetTitle:     spreadsheetTitle,
+		sheetTitle:           sheetProperties.Title,
+		driveFile:            args.DriveFile,
+		cellRange:            args.Range,
+		namedRange:           args.NamedRange,
+		batchSize:            args.BatchSize,
+		rowCount:             gridRowCount(sheetProperties),
 		sheetSvc:             sheetService,
 		dateTimeRenderOption: args.DateTimeRenderOption,
 		valueRenderOption:    args.ValueRenderOption,
+		headerRow:            args.HeaderRow,
+		typedValues:          args.TypedValues,
+		location:             location,
+		detectUpdates:        args.DetectUpdates,
+		detectDeletes:        args.DetectDeletes,
+		identityColumn:       args.RowIdentityColumn,
+		keyColumns:           args.KeyColumns,
+		skipEmptyKeys:        args.SkipEmptyKeys,
+		holdEmptyKeys:        args.HoldEmptyKeys,
+		createdAtColumn:      args.CreatedAtColumn,
+		createdAtLayout:      createdAtLayout,
+		createdAtLocation:    createdAtLocation,
+		rowStates:            make(map[int64]state.Row),
 	}, nil
 }
 
-// GetSheetRecords returns the list of records up to a maximum of 1000 rows(default limit)
-// added after the row offset of last successfully read record
-func (b *BatchReader) GetSheetRecords(ctx context.Context, offset int64) ([]opencdc.Record, error) {
-	if b.nextRun.After(time.Now()) {
-		return nil, nil
+// startRow returns the 0-based index of the first row to fetch, for the row offset of the last read record
+func (b *BatchReader) startRow(offset int64) int64 {
+	start := offset
+	if b.detectUpdates || b.detectDeletes {
+		// scan the whole sheet, to compare the already read rows with their last known content
+		start = 0
+	}
+	// the header row, and any row above it, is never emitted as a record, nor the rows above the range,
+	// nor the rows of the pages already read
+	return max(start, b.headerRow, b.cellRange.StartRow, b.pageEnd)
+}
+
+// readerState is the state of a BatchReader moved by the read of its sheet, set by setState once the records
+// of all the sheets read along are built, so a failed read leaves the state of all the readers unchanged
+type readerState struct {
+	// pageEnd is the start of the next page, see BatchReader.pageEnd
+	pageEnd int64
+	// emptyFrom and emptyTail locate the empty rows found by the walk of the pages, see BatchReader.emptyFrom
+	emptyFrom int64
+	emptyTail bool
+	// rowStates are the current row states, nil unless the changes of the rows are detected
+	rowStates map[int64]state.Row
+	// rowIdentities are the values of the identityColumn, or the keys, of the rows read, by row number,
+	// nil unless the rows are identified by a column value. They're set along with the rowStates, as their Identity
+	rowIdentities map[int64]string
+	// heldRow is the row number of the first row with blank key cells after the row offset, held with holdEmptyKeys,
+	// 0 if none. The rows from heldRow are read again on the next poll
+	heldRow int64
+}
+
+// setState sets the state of the reader moved by the read of its sheet
+func (b *BatchReader) setState(s readerState) {
+	b.pageEnd = s.pageEnd
+	b.emptyFrom = s.emptyFrom
+	b.emptyTail = s.emptyTail
+	if s.rowStates != nil {
+		b.rowStates = s.rowStates
+	}
+}
+
+// sheetRecords converts the value ranges fetched from the start row, matching the data filters of the sheet, to records,
+// returning the state of the reader after the read, to be set with setState
+func (b *BatchReader) sheetRecords(
+	ctx context.Context,
+	valueRanges []*sheets.MatchedValueRange,
+	start, offset int64,
+) ([]opencdc.Record, readerState, error) {
+	if b.namedRange != "" {
+		var err error
+		if valueRanges, err = b.namedRangeValues(ctx, valueRanges, start); err != nil {
+			return nil, readerState{}, err
+		}
+	}
+	var rowData []*sheets.RowData
+	if b.needsRowData(valueRanges, start) {
+		var err error
+		if rowData, err = b.getRowData(ctx, start); err != nil {
+			return nil, readerState{}, err
+		}
 	}
+	return b.pageRecords(valueRanges, rowData, start, offset)
+}
 
-	res, err := b.sheetSvc.Spreadsheets.Values.BatchGetByDataFilter(b.spreadsheetID, b.getDataFilter(offset)).Context(ctx).Do()
+// needsRowData returns whether the cell data of the rows fetched from the start row is needed to type their values,
+// or to tell the date-time serials of the created-at cells from the numbers, which are only rendered unformatted
+func (b *BatchReader) needsRowData(valueRanges []*sheets.MatchedValueRange, start int64) bool {
+	createdAtNumbers := b.createdAtColumn != "" && b.valueRenderOption != "FORMATTED_VALUE"
+	return (b.typedValues || createdAtNumbers) && len(valueRanges) > 0 && !b.cellRange.exhausted(start)
+}
+
+// pageRecords converts the value ranges of the sheet rows fetched from the start row to records, the values being typed
+// with the cell data of the rows, returning the state of the reader after the read, to be set with setState
+func (b *BatchReader) pageRecords(
+	valueRanges []*sheets.MatchedValueRange,
+	rowData []*sheets.RowData,
+	start, offset int64,
+) ([]opencdc.Record, readerState, error) {
+	// the next page is only read once the records of this page are built, a failed page is fetched again
+	next := b.pageState(valueRanges, start)
+	if b.typedValues && b.needsRowData(valueRanges, start) {
+		// the data rows are the last value range, after the header row
+		b.typeValues(valueRanges[len(valueRanges)-1].ValueRange, rowData)
+	}
+
+	records, rows, err := b.valueRangesToRecords(valueRanges, rowData, start)
+	next.rowIdentities = rows.identities
+	if err == nil && (b.detectUpdates || b.detectDeletes) {
+		records, next.rowStates, err = b.detectRowChanges(records, next.rowIdentities, offset)
+	}
+	if err == nil {
+		next.heldRow = rows.heldRow(offset)
+		records, err = holdRows(records, next.heldRow)
+	}
 	if err != nil {
-		if googleapi.IsNotModified(err) {
-			return nil, nil
+		return nil, readerState{}, err
+	}
+	if next.heldRow > 0 {
+		// the next page starts at the held row, fetched from the row offset on the next poll
+		next.pageEnd = 0
+	}
+	return records, next, nil
+}
+
+// holdRows returns the records without the rows from the held row, 0 if none, the records before the row offset,
+// and the deletes, being kept
+func holdRows(records []opencdc.Record, heldRow int64) ([]opencdc.Record, error) {
+	if heldRow == 0 {
+		return records, nil
+	}
+	kept := make([]opencdc.Record, 0, len(records))
+	for _, record := range records {
+		pos, err := position.ParseRecordPosition(record.Position)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse record position: %w", err)
 		}
-		if gerr, ok := err.(*googleapi.Error); ok && gerr.Code == http.StatusTooManyRequests {
-			b.retryCount++
-			duration := time.Duration(b.retryCount * int64(b.pollingPeriod)) // exponential back off
-			b.nextRun = time.Now().Add(duration)
-			sdk.Logger(ctx).Error().Err(gerr).
-				Int64("retry_count", b.retryCount).
-				Float64("wait_duration", duration.Seconds()).
-				Msg("exponential back off, rate limit exceeded")
-			return nil, nil
+		if pos.RowOffset < heldRow {
+			kept = append(kept, record)
 		}
-		return nil, fmt.Errorf("error getting sheet(gid:%v) values, %w", b.sheetID, err)
 	}
+	return kept, nil
+}
 
-	b.retryCount = 0
-	return b.valueRangesToRecords(res.ValueRanges, offset)
+// checkRetryable returns nil if the request failed with an error to be retried on the next poll,
+// i.e. not modified, or the rate limit exceeded, in which case the next run is delayed with exponential back off
+func (b *backoff) checkRetryable(ctx context.Context, err error) error {
+	var gerr *googleapi.Error
+	if !errors.As(err, &gerr) {
+		return err
+	}
+	if gerr.Code == http.StatusNotModified {
+		return nil
+	}
+	if gerr.Code == http.StatusTooManyRequests {
+		b.retryCount++
+		duration := time.Duration(b.retryCount * int64(b.pollingPeriod)) // exponential back off
+		b.nextRun = time.Now().Add(duration)
+		sdk.Logger(ctx).Error().Err(gerr).
+			Int64("retry_count", b.retryCount).
+			Float64("wait_duration", duration.Seconds()).
+			Msg("exponential back off, rate limit exceeded")
+		return nil
+	}
+	return err
 }
 
 func (b *BatchReader) getDataFilter(offset int64) *sheets.BatchGetValuesByDataFilterRequest {
-	dataFilters := make([]*sheets.DataFilter, 0)
-	dataFilters = append(dataFilters, &sheets.DataFilter{
-		GridRange: &sheets.GridRange{
-			SheetId:       b.sheetID,
-			StartRowIndex: offset,
-		},
-	})
 	return &sheets.BatchGetValuesByDataFilterRequest{
-		DataFilters:          dataFilters,
+		DataFilters:          b.dataFilters(offset),
 		DateTimeRenderOption: b.dateTimeRenderOption,
 		MajorDimension:       majorDimension,
 		ValueRenderOption:    b.valueRenderOption,
 	}
 }
 
-func (b *BatchReader) valueRangesToRecords(valueRanges []*sheets.MatchedValueRange, offset int64) ([]opencdc.Record, error) {
+// dataFilters returns the data filters of the sheet rows in the range from the offset, preceded by the header row if any.
+// The rows filter is left out once all the rows of the range are read.
+func (b *BatchReader) dataFilters(offset int64) []*sheets.DataFilter {
+	if b.namedRange != "" {
+		// the named range is fetched as a whole, its header row and rows being split by namedRangeValues
+		return []*sheets.DataFilter{{A1Range: b.namedRange}}
+	}
+	dataFilters := make([]*sheets.DataFilter, 0)
+	if b.headerRow > 0 {
+		// fetch the header row in the same request, so the records always use the current header names
+		header := b.cellRange.gridRange(b.sheetID, b.headerRow-1)
+		header.EndRowIndex = b.headerRow
+		dataFilters = append(dataFilters, &sheets.DataFilter{GridRange: header})
+	}
+	if b.cellRange.exhausted(offset) {
+		return dataFilters
+	}
+	return append(dataFilters, &sheets.DataFilter{GridRange: b.pageGridRange(offset)})
+}
+
+// pageGridRange returns the grid range of the rows fetched from the offset, within the page of batchSize rows if set
+func (b *BatchReader) pageGridRange(offset int64) *sheets.GridRange {
+	rows := b.cellRange.gridRange(b.sheetID, offset)
+	if b.batchSize > 0 {
+		rows.EndRowIndex = b.pageLimit(offset)
+	}
+	return rows
+}
+
+// pageLimit returns the 0-based index of the row after the page of batchSize rows from the offset, within the range
+func (b *BatchReader) pageLimit(offset int64) int64 {
+	end := offset + b.batchSize
+	if b.cellRange.EndRow > 0 {
+		end = min(end, b.cellRange.EndRow)
+	}
+	return end
+}
+
+// pageState returns the state of the paging after the page fetched from the start row. The next page starts
+// at pageEnd, the row after the page, if the page holds batchSize rows, or if it's an empty page within the sheet grid,
+// 0 otherwise. The trailing empty rows of a page aren't returned by the API, the rows after a page ending with empty rows
+// are fetched on the next poll. The empty pages are walked until the end of the sheet grid once, the empty tail being
+// remembered so the next polls only fetch the page after the offset.
+func (b *BatchReader) pageState(valueRanges []*sheets.MatchedValueRange, start int64) readerState {
+	if b.batchSize == 0 || len(valueRanges) == 0 || b.cellRange.exhausted(start) {
+		return readerState{}
+	}
+	end := b.pageLimit(start)
+	// the page ends with the range
+	last := end-start < b.batchSize
+	var rows int64
+	if data := valueRanges[len(valueRanges)-1].ValueRange; data != nil {
+		rows = int64(len(data.Values))
+	}
+
+	if rows == 0 {
+		if b.emptyTail && start >= b.emptyFrom {
+			// the empty tail was already walked
+			return readerState{emptyFrom: b.emptyFrom, emptyTail: true}
+		}
+		from := start
+		if b.pageEnd == start && b.emptyFrom < start {
+			// the walk of the empty pages goes on
+			from = b.emptyFrom
+		}
+		if last || end >= b.rowCount || (b.emptyTail && end >= b.emptyFrom) {
+			return readerState{emptyFrom: from, emptyTail: true}
+		}
+		return readerState{pageEnd: end, emptyFrom: from}
+	}
+
+	next := readerState{emptyFrom: start + rows, emptyTail: last}
+	if b.emptyTail {
+		// the empty tail starts after the rows of the page, unless the page is above it
+		next.emptyTail = true
+		if b.emptyFrom > end {
+			next.emptyFrom = b.emptyFrom
+		}
+	}
+	if rows == b.batchSize && !last {
+		next.pageEnd = end
+	}
+	return next
+}
+
+// morePages reports whether the next page is fetched right away, after a page of batchSize rows.
+// The empty pages are walked one per poll, so a large blank sheet grid doesn't exceed the requests quota.
+func (b *BatchReader) morePages() bool {
+	return b.pageEnd > 0 && b.pageEnd <= b.emptyFrom
+}
+
+// pageRows are the rows converted to records by valueRangesToRecords, besides the records
+type pageRows struct {
+	// identities are the values of the identityColumn, or the keys, of the rows, by row number,
+	// nil unless the rows are identified by a column value
+	identities map[int64]string
+	// emptyKeys are the row numbers of the rows with blank key cells held with holdEmptyKeys, in row order
+	emptyKeys []int64
+}
+
+// heldRow returns the row number of the first row with blank key cells after the row offset, 0 if none
+func (r pageRows) heldRow(offset int64) int64 {
+	for _, rowNumber := range r.emptyKeys {
+		if rowNumber > offset {
+			return rowNumber
+		}
+	}
+	return 0
+}
+
+// valueRangesToRecords converts the value ranges of the rows fetched from the offset to records, the cell data
+// of the rows, if fetched, giving the number format of the created-at cells. It returns the identities of the rows,
+// by row number, if the rows are identified by a column value, and the held rows
+func (b *BatchReader) valueRangesToRecords(
+	valueRanges []*sheets.MatchedValueRange,
+	rowData []*sheets.RowData,
+	offset int64,
+) ([]opencdc.Record, pageRows, error) {
 	records := make([]opencdc.Record, 0)
+	var rows pageRows
+
+	var headers []string
+	if b.headerRow > 0 {
+		// the value ranges are returned in the order of the data filters, the header row being the first one
+		if len(valueRanges) == 0 {
+			return records, rows, nil
+		}
+		var headerRow []any
+		if header := valueRanges[0].ValueRange; header != nil && len(header.Values) > 0 {
+			headerRow = header.Values[0]
+		}
+		headers = headerNames(headerRow, b.cellRange.StartColumn)
+		valueRanges = valueRanges[1:]
+	}
+
+	columns, err := b.resolveRowColumns(headers)
+	if err != nil {
+		return records, pageRows{}, err
+	}
+	if b.hasRowIdentity() {
+		rows.identities = make(map[int64]string)
+	}
 
 	// As we can fetch multiple ranges in one BatchGetByDataFilter request
 	// iterate over all the value ranges fetched from the Google sheet BatchGet API request
@@ -139,25 +533,107 @@ func (b *BatchReader) valueRangesToRecords(valueRanges []*sheets.MatchedValueRan
 			if len(rowValue) == 0 {
 				continue
 			}
-			rawData, err := json.Marshal(rowValue)
-			if err != nil {
-				return records, fmt.Errorf("error marshaling the map: %w", err)
-			}
 			rowOffset := offset + int64(index) + 1
-			lastRowPosition := position.SheetPosition{
-				RowOffset:     rowOffset,
-				SpreadsheetID: b.spreadsheetID,
-				SheetID:       b.sheetID,
+			createdAtCell := cellData(rowData, index, columns.createdAtIndex)
+			record, ok, err := b.rowRecord(columns, rowValue, createdAtCell, rowOffset, rows.identities)
+			switch {
+			case err != nil:
+				return records, pageRows{}, err
+			case ok:
+				records = append(records, record)
+			case b.holdEmptyKeys:
+				rows.emptyKeys = append(rows.emptyKeys, rowOffset)
 			}
+		}
+	}
+	return records, rows, nil
+}
 
-			metadata := opencdc.Metadata{}
-			metadata.SetCreatedAt(time.Now())
+// rowColumns are the header names of the rows, and the columns of the rows resolved with them,
+// the indices being -1 when the column isn't set
+type rowColumns struct {
+	headers        []string
+	keyColumns     []keyColumn
+	identityIndex  int
+	createdAtIndex int
+}
 
-			record := sdk.Util.Source.NewRecordSnapshot(lastRowPosition.RecordPosition(), metadata,
-				opencdc.RawData(fmt.Sprintf("%d", rowOffset)), opencdc.RawData(rawData))
+// resolveRowColumns resolves the key, row identity and created-at columns using the current header names
+func (b *BatchReader) resolveRowColumns(headers []string) (rowColumns, error) {
+	columns := rowColumns{headers: headers, identityIndex: -1, createdAtIndex: -1}
+	var err error
+	if columns.keyColumns, err = b.resolveKeyColumns(headers); err != nil {
+		return rowColumns{}, err
+	}
+	if b.identityColumn != "" {
+		if columns.identityIndex, err = resolveColumn(b.identityColumn, headers, b.cellRange); err != nil {
+			return rowColumns{}, fmt.Errorf("invalid row identity column: %w", err)
+		}
+	}
+	if b.createdAtColumn != "" {
+		if columns.createdAtIndex, err = resolveColumn(b.createdAtColumn, headers, b.cellRange); err != nil {
+			return rowColumns{}, fmt.Errorf("invalid created-at column: %w", err)
+		}
+	}
+	return columns, nil
+}
+
+// rowRecord returns the record of the row with the row offset, adding the identity of the row to the rowIdentities,
+// false if the row is skipped, or held, for its empty key. The createdAtCell is the cell data of the created-at cell,
+// nil if not fetched
+func (b *BatchReader) rowRecord(
+	columns rowColumns,
+	rowValue []any,
+	createdAtCell *sheets.CellData,
+	rowOffset int64,
+	rowIdentities map[int64]string,
+) (opencdc.Record, bool, error) {
+	var payload opencdc.Data
+	if b.headerRow > 0 {
+		payload = rowToStructuredData(columns.headers, rowValue, b.cellRange.StartColumn)
+	} else {
+		rawData, err := json.Marshal(rowValue)
+		if err != nil {
+			return opencdc.Record{}, false, fmt.Errorf("error marshaling the map: %w", err)
+		}
+		payload = opencdc.RawData(rawData)
+	}
+	if columns.identityIndex >= 0 && columns.identityIndex < len(rowValue) {
+		rowIdentities[rowOffset] = strings.TrimSpace(fmt.Sprint(rowValue[columns.identityIndex]))
+	}
+	var key opencdc.Data = opencdc.RawData(fmt.Sprintf("%d", rowOffset))
+	if len(columns.keyColumns) > 0 {
+		structuredKey := rowKey(columns.keyColumns, rowValue)
+		if structuredKey == nil {
+			if b.skipEmptyKeys || b.holdEmptyKeys {
+				return opencdc.Record{}, false, nil
+			}
+			return opencdc.Record{}, false, fmt.Errorf("row %d has an empty key, key columns: %s", rowOffset, strings.Join(b.keyColumns, ","))
+		}
+		key = structuredKey
+		if columns.identityIndex < 0 {
+			// the rows are identified by their key, when the row identity column isn't set
+			rowIdentities[rowOffset] = string(structuredKey.Bytes())
+		}
+	}
+	lastRowPosition := position.SheetPosition{
+		RowOffset:     rowOffset,
+		SpreadsheetID: b.spreadsheetID,
+		SheetID:       b.sheetID,
+	}
 
-			records = append(records, record)
+	metadata := b.rowMetadata(rowOffset)
+	metadata[MetadataRange] = b.rowRange(rowOffset, len(rowValue))
+	if columns.createdAtIndex >= 0 && columns.createdAtIndex < len(rowValue) {
+		createdAt, ok, err := b.createdAt(rowValue[columns.createdAtIndex], createdAtCell)
+		if err != nil {
+			return opencdc.Record{}, false, fmt.Errorf("row %d has an invalid created-at value %q: %w",
+				rowOffset, fmt.Sprint(rowValue[columns.createdAtIndex]), err)
+		}
+		if ok {
+			metadata.SetCreatedAt(createdAt)
 		}
 	}
-	return records, nil
+
+	return sdk.Util.Source.NewRecordSnapshot(lastRowPosition.RecordPosition(), metadata, key, payload), true, nil
 }
@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/state"
 	"github.com/conduitio/conduit-commons/opencdc"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/oauth2"
@@ -30,22 +31,32 @@ import (
 )
 
 func TestNewBatchReader(t *testing.T) {
-	got, err := NewBatchReader(context.Background(), BatchReaderArgs{
-		OAuthToken:           &oauth2.Token{},
-		OAuthConfig:          &oauth2.Config{},
+	testServer := newMetadataServer(t, 1234, "Sheet1")
+	defer testServer.Close()
+
+	reader, err := NewMultiReader(context.Background(), MultiReaderArgs{BatchReaderArgs: BatchReaderArgs{
+		ClientArgs: ClientArgs{
+			TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+			Endpoint:    testServer.URL,
+		},
 		SpreadsheetID:        "dummy_spreadsheet",
 		SheetID:              1234,
 		DateTimeRenderOption: "SOME_VALUE",
 		ValueRenderOption:    "SOME_OTHER_VALUE",
 		PollingPeriod:        3 * time.Second,
-	})
+	}})
 	assert.NoError(t, err)
+	assert.Equal(t, backoff{pollingPeriod: 3 * time.Second}, reader.backoff)
+	assert.Len(t, reader.readers, 1)
+	got := reader.readers[0]
 	want := &BatchReader{
 		spreadsheetID:        "dummy_spreadsheet",
 		sheetID:              1234,
+		spreadsheetTitle:     "Dummy",
+		sheetTitle:           "Sheet1",
 		dateTimeRenderOption: "SOME_VALUE",
 		valueRenderOption:    "SOME_OTHER_VALUE",
-		pollingPeriod:        3 * time.Second,
+		rowStates:            map[int64]state.Row{},
 	}
 	want.sheetSvc = got.sheetSvc
 	assert.Equal(t, want, got)
@@ -86,7 +97,7 @@ func TestBatchReader_valueRangesToRecords(t *testing.T) {
 		dateTimeRenderOption: "DATE_TIME_OPTION",
 		valueRenderOption:    "VALUE_OPTION",
 	}
-	out, err := br.valueRangesToRecords(in, 10)
+	out, _, err := br.valueRangesToRecords(in, nil, 10)
 	assert.NoError(t, err)
 
 	want := []opencdc.Record{
@@ -130,6 +141,159 @@ func (t *testHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(t.resp)
 }
 
+func TestBatchReader_getDataFilter_HeaderRow(t *testing.T) {
+	br := &BatchReader{
+		sheetID:   1234,
+		headerRow: 2,
+	}
+	want := []*sheets.DataFilter{
+		{GridRange: &sheets.GridRange{SheetId: 1234, StartRowIndex: 1, EndRowIndex: 2}},
+		{GridRange: &sheets.GridRange{SheetId: 1234, StartRowIndex: 10}},
+	}
+
+	assert.Equal(t, want, br.getDataFilter(10).DataFilters)
+}
+
+func TestBatchReader_getDataFilter_BatchSize(t *testing.T) {
+	br := &BatchReader{
+		sheetID:   1234,
+		batchSize: 100,
+		cellRange: CellRange{EndRow: 150},
+	}
+	want := []*sheets.DataFilter{
+		{GridRange: &sheets.GridRange{SheetId: 1234, StartRowIndex: 10, EndRowIndex: 110}},
+	}
+	assert.Equal(t, want, br.getDataFilter(10).DataFilters)
+
+	// the last page ends with the range
+	want = []*sheets.DataFilter{
+		{GridRange: &sheets.GridRange{SheetId: 1234, StartRowIndex: 110, EndRowIndex: 150}},
+	}
+	assert.Equal(t, want, br.getDataFilter(110).DataFilters)
+}
+
+func TestBatchReader_pageState(t *testing.T) {
+	rows := func(n int) []*sheets.MatchedValueRange {
+		values := make([][]interface{}, n)
+		for i := range values {
+			values[i] = []interface{}{i}
+		}
+		return []*sheets.MatchedValueRange{{ValueRange: &sheets.ValueRange{Values: values}}}
+	}
+	testCases := []struct {
+		name        string
+		reader      *BatchReader
+		valueRanges []*sheets.MatchedValueRange
+		start       int64
+		want        readerState
+	}{
+		{
+			name:        "full page",
+			reader:      &BatchReader{batchSize: 3, rowCount: 1000},
+			valueRanges: rows(3),
+			start:       10,
+			want:        readerState{pageEnd: 13, emptyFrom: 13},
+		},
+		{
+			name:        "last page",
+			reader:      &BatchReader{batchSize: 3, rowCount: 1000},
+			valueRanges: rows(2),
+			start:       10,
+			want:        readerState{emptyFrom: 12},
+		},
+		{
+			name:        "empty page within the grid",
+			reader:      &BatchReader{batchSize: 3, rowCount: 1000},
+			valueRanges: rows(0),
+			start:       10,
+			want:        readerState{pageEnd: 13, emptyFrom: 10},
+		},
+		{
+			name:        "next empty page of the walk",
+			reader:      &BatchReader{batchSize: 3, rowCount: 1000, pageEnd: 13, emptyFrom: 10},
+			valueRanges: rows(0),
+			start:       13,
+			want:        readerState{pageEnd: 16, emptyFrom: 10},
+		},
+		{
+			name:        "empty page at the end of the grid",
+			reader:      &BatchReader{batchSize: 3, rowCount: 16, pageEnd: 13, emptyFrom: 10},
+			valueRanges: rows(0),
+			start:       13,
+			want:        readerState{emptyFrom: 10, emptyTail: true},
+		},
+		{
+			name:        "empty page of the known tail",
+			reader:      &BatchReader{batchSize: 3, rowCount: 1000, emptyFrom: 10, emptyTail: true},
+			valueRanges: rows(0),
+			start:       10,
+			want:        readerState{emptyFrom: 10, emptyTail: true},
+		},
+		{
+			name:        "rows added to the known tail",
+			reader:      &BatchReader{batchSize: 3, rowCount: 1000, emptyFrom: 10, emptyTail: true},
+			valueRanges: rows(2),
+			start:       10,
+			want:        readerState{emptyFrom: 12, emptyTail: true},
+		},
+		{
+			name:        "full page above the known tail",
+			reader:      &BatchReader{batchSize: 3, rowCount: 1000, emptyFrom: 50, emptyTail: true},
+			valueRanges: rows(3),
+			start:       10,
+			want:        readerState{pageEnd: 13, emptyFrom: 50, emptyTail: true},
+		},
+		{
+			name:        "page ending with the range",
+			reader:      &BatchReader{batchSize: 3, rowCount: 1000, cellRange: CellRange{EndRow: 12}},
+			valueRanges: rows(2),
+			start:       10,
+			want:        readerState{emptyFrom: 12, emptyTail: true},
+		},
+		{
+			name:        "no batch size",
+			reader:      &BatchReader{rowCount: 1000},
+			valueRanges: rows(3),
+			start:       10,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.reader.pageState(tc.valueRanges, tc.start))
+		})
+	}
+}
+
+func TestBatchReader_valueRangesToRecords_HeaderRow(t *testing.T) {
+	in := []*sheets.MatchedValueRange{
+		{ValueRange: &sheets.ValueRange{
+			Range:  "Sheet1!A1:Z1",
+			Values: [][]any{{"id", "", "name", "name"}},
+		}},
+		{ValueRange: &sheets.ValueRange{
+			Range: "Sheet1!A2:Z3",
+			Values: [][]any{
+				{"1", "x", "alice", "bob", "extra"},
+				{"2"},
+			},
+		}},
+	}
+
+	br := &BatchReader{
+		sheetID:       1234,
+		spreadsheetID: "dummy_spreadsheet",
+		headerRow:     1,
+	}
+	out, _, err := br.valueRangesToRecords(in, nil, 1)
+	assert.NoError(t, err)
+	assert.Len(t, out, 2)
+
+	assert.Equal(t, opencdc.StructuredData{"id": "1", "B": "x", "name": "alice", "name_2": "bob", "E": "extra"}, out[0].Payload.After)
+	assert.Equal(t, opencdc.Position(`{"row_offset":2,"spreadsheet_id":"dummy_spreadsheet","sheet_id":1234}`), out[0].Position)
+	assert.Equal(t, opencdc.StructuredData{"id": "2", "B": nil, "name": nil, "name_2": nil}, out[1].Payload.After)
+	assert.Equal(t, opencdc.Position(`{"row_offset":3,"spreadsheet_id":"dummy_spreadsheet","sheet_id":1234}`), out[1].Position)
+}
+
 func TestBatchReader_GetSheetRecords_429(t *testing.T) {
 	header := http.Header{}
 	header.Set("Retry-After", "93")
@@ -146,15 +310,14 @@ func TestBatchReader_GetSheetRecords_429(t *testing.T) {
 		option.WithEndpoint(testServer.URL),
 		option.WithHTTPClient(&http.Client{}))
 	assert.NoError(t, err)
-	cursor := &BatchReader{
-		nextRun:       time.Time{},
+	cursor := &MultiReader{
+		backoff:       backoff{nextRun: time.Time{}, pollingPeriod: 10 * time.Second},
 		spreadsheetID: "dummy_spreadsheet",
-		sheetID:       1234,
 		sheetSvc:      sheetSvc,
-		pollingPeriod: 10 * time.Second,
+		readers:       []*BatchReader{{spreadsheetID: "dummy_spreadsheet", sheetID: 1234, sheetSvc: sheetSvc}},
 	}
 	ctx := context.Background()
-	recs, err := cursor.GetSheetRecords(ctx, 10)
+	recs, err := cursor.GetSheetRecords(ctx, map[int64]int64{1234: 10})
 	assert.NoError(t, err)
 	assert.Len(t, recs, 0)
 	assert.GreaterOrEqual(t, cursor.nextRun.Unix(), time.Now().Add(9*time.Second).Unix())
@@ -176,16 +339,15 @@ func TestBatchReader_GetSheetRecords_500(t *testing.T) {
 		option.WithEndpoint(testServer.URL),
 		option.WithHTTPClient(&http.Client{}))
 	assert.NoError(t, err)
-	cursor := &BatchReader{
-		nextRun:       time.Time{},
+	cursor := &MultiReader{
+		backoff:       backoff{nextRun: time.Time{}, pollingPeriod: 10 * time.Second},
 		spreadsheetID: "dummy_spreadsheet",
-		sheetID:       1234,
 		sheetSvc:      sheetSvc,
-		pollingPeriod: 10 * time.Second,
+		readers:       []*BatchReader{{spreadsheetID: "dummy_spreadsheet", sheetID: 1234, sheetSvc: sheetSvc}},
 	}
 	ctx := context.Background()
-	_, err = cursor.GetSheetRecords(ctx, 10)
-	assert.EqualError(t, err, "error getting sheet(gid:1234) values, googleapi: got HTTP response code 500 with body: ")
+	_, err = cursor.GetSheetRecords(ctx, map[int64]int64{1234: 10})
+	assert.EqualError(t, err, "error getting spreadsheet(dummy_spreadsheet) values, googleapi: got HTTP response code 500 with body: ")
 }
 
 func TestBatchReader_GetSheetRecords_304(t *testing.T) {
@@ -204,15 +366,14 @@ func TestBatchReader_GetSheetRecords_304(t *testing.T) {
 		option.WithEndpoint(testServer.URL),
 		option.WithHTTPClient(&http.Client{}))
 	assert.NoError(t, err)
-	cursor := &BatchReader{
-		nextRun:       time.Time{},
+	cursor := &MultiReader{
+		backoff:       backoff{nextRun: time.Time{}, pollingPeriod: 10 * time.Second},
 		spreadsheetID: "dummy_spreadsheet",
-		sheetID:       1234,
 		sheetSvc:      sheetSvc,
-		pollingPeriod: 10 * time.Second,
+		readers:       []*BatchReader{{spreadsheetID: "dummy_spreadsheet", sheetID: 1234, sheetSvc: sheetSvc}},
 	}
 	ctx := context.Background()
-	recs, err := cursor.GetSheetRecords(ctx, 10)
+	recs, err := cursor.GetSheetRecords(ctx, map[int64]int64{1234: 10})
 	assert.NoError(t, err)
 	assert.Nil(t, recs)
 }
@@ -0,0 +1,148 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-google-sheets/config"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// ClientArgs are the options of the HTTP clients used for the Google APIs, shared by the reader and the writer
+type ClientArgs struct {
+	TokenSource oauth2.TokenSource
+	// Endpoint overrides the base URL of the Sheets API, e.g. for a local emulator,
+	// the Drive API is then served under its drive/v3/ path
+	Endpoint string
+	// ProxyURL is the HTTP proxy, the proxy from the environment is used when nil
+	ProxyURL *url.URL
+	// Timeout is the timeout of a single request, no timeout when zero
+	Timeout time.Duration
+	// CABundle is a PEM encoded CA bundle trusted in addition to the system roots
+	CABundle []byte
+	// QuotaProject is the project the quota and billing of the API requests are attributed to
+	QuotaProject string
+}
+
+// NewClientArgs returns the client args of the connector config, along with the token source of its auth mode.
+// The token requests go through the same proxy and CA bundle as the API requests.
+func NewClientArgs(ctx context.Context, cfg config.Config) (ClientArgs, error) {
+	args := ClientArgs{
+		Endpoint:     cfg.Endpoint,
+		ProxyURL:     cfg.ProxyURL,
+		Timeout:      cfg.RequestTimeout,
+		CABundle:     cfg.CABundle,
+		QuotaProject: cfg.QuotaProject,
+	}
+	httpClient, err := NewHTTPClient(args)
+	if err != nil {
+		return ClientArgs{}, fmt.Errorf("invalid http client configuration: %w", err)
+	}
+
+	args.TokenSource, err = cfg.TokenSource(context.WithValue(ctx, oauth2.HTTPClient, httpClient))
+	if err != nil {
+		return ClientArgs{}, fmt.Errorf("invalid auth configuration: %w", err)
+	}
+	return args, nil
+}
+
+// quotaProjectHeader is the header attributing the quota of a request to a project,
+// set by a transport since option.WithQuotaProject is ignored along with option.WithHTTPClient
+const quotaProjectHeader = "X-Goog-User-Project"
+
+// quotaProjectTransport sets the quota project header on every request
+type quotaProjectTransport struct {
+	base         http.RoundTripper
+	quotaProject string
+}
+
+func (t *quotaProjectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request, set the header on a clone
+	req = req.Clone(req.Context())
+	req.Header.Set(quotaProjectHeader, t.quotaProject)
+	return t.base.RoundTrip(req)
+}
+
+// NewHTTPClient returns the unauthenticated HTTP client honoring the proxy, timeout and CA bundle of the args.
+// It's the base client of the API requests, and can be used for the token requests as the oauth2.HTTPClient context value.
+func NewHTTPClient(args ClientArgs) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if args.ProxyURL != nil {
+		transport.Proxy = http.ProxyURL(args.ProxyURL)
+	}
+
+	if len(args.CABundle) > 0 {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(args.CABundle) {
+			return nil, fmt.Errorf("no PEM encoded certificates found in the CA bundle")
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    rootCAs,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   args.Timeout,
+	}, nil
+}
+
+// newService is the single factory of the sheets service clients, authenticating the requests
+// with the token source on top of the base HTTP client
+func newService(ctx context.Context, args ClientArgs) (*sheets.Service, error) {
+	client, err := newAuthClient(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []option.ClientOption{option.WithHTTPClient(client)}
+	if args.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(args.Endpoint))
+	}
+
+	sheetService, err := sheets.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating sheets service client: %w", err)
+	}
+	return sheetService, nil
+}
+
+// newAuthClient returns the base HTTP client authenticating the requests with the token source
+func newAuthClient(ctx context.Context, args ClientArgs) (*http.Client, error) {
+	baseClient, err := NewHTTPClient(args)
+	if err != nil {
+		return nil, err
+	}
+
+	client := oauth2.NewClient(context.WithValue(ctx, oauth2.HTTPClient, baseClient), args.TokenSource)
+	client.Timeout = args.Timeout
+	if args.QuotaProject != "" {
+		client.Transport = &quotaProjectTransport{base: client.Transport, quotaProject: args.QuotaProject}
+	}
+	return client, nil
+}
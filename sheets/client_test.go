@@ -0,0 +1,126 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestNewWriter_ResolvesSheetNameFromEndpoint(t *testing.T) {
+	th := &testHandler{
+		t:          t,
+		url:        &url.URL{Path: "/v4/spreadsheets/dummy_spreadsheet", RawQuery: spreadsheetQuery},
+		statusCode: 200,
+		resp:       []byte(`{"sheets":[{"properties":{"sheetId":0,"title":"Sheet1","index":0}},{"properties":{"sheetId":1234,"title":"Orders","index":1}}]}`),
+		header:     http.Header{},
+	}
+	testServer := httptest.NewServer(th)
+	defer testServer.Close()
+
+	writer, err := NewWriter(context.Background(), WriterArgs{
+		ClientArgs: ClientArgs{
+			TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+			Endpoint:    testServer.URL,
+		},
+		SpreadsheetID: "dummy_spreadsheet",
+		SheetID:       1234,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Orders", writer.sheetName)
+
+	_, err = NewWriter(context.Background(), WriterArgs{
+		ClientArgs: ClientArgs{
+			TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+			Endpoint:    testServer.URL,
+		},
+		SpreadsheetID: "dummy_spreadsheet",
+		SheetID:       1234,
+		SheetName:     "Sheet1",
+	})
+	assert.EqualError(t, err, `sheet(gid:1234) is named "Orders", which doesn't match the sheet name "Sheet1"`)
+}
+
+func TestNewService_QuotaProject(t *testing.T) {
+	var gotQuotaProject, gotAuthorization string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuotaProject = r.Header.Get("x-goog-user-project")
+		gotAuthorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"sheets":[{"properties":{"sheetId":0,"title":"Sheet1","index":0}}]}`))
+	}))
+	defer testServer.Close()
+
+	_, err := NewMultiReader(context.Background(), MultiReaderArgs{BatchReaderArgs: BatchReaderArgs{
+		ClientArgs: ClientArgs{
+			TokenSource:  oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+			Endpoint:     testServer.URL,
+			QuotaProject: "billing-project",
+		},
+		SpreadsheetID: "dummy_spreadsheet",
+		SheetID:       -1,
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, "billing-project", gotQuotaProject)
+	assert.Equal(t, "Bearer dummy", gotAuthorization)
+}
+
+func TestNewHTTPClient_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a forward proxy receives the absolute URL of the request
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	assert.NoError(t, err)
+
+	client, err := NewHTTPClient(ClientArgs{ProxyURL: proxyURL})
+	assert.NoError(t, err)
+
+	resp, err := client.Get("http://sheets.example.com/v4/spreadsheets/dummy")
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "http://sheets.example.com/v4/spreadsheets/dummy", proxied)
+}
+
+func TestNewHTTPClient_Timeout(t *testing.T) {
+	done := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-done
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+	defer close(done)
+
+	client, err := NewHTTPClient(ClientArgs{Timeout: 50 * time.Millisecond})
+	assert.NoError(t, err)
+
+	_, err = client.Get(testServer.URL)
+	assert.ErrorContains(t, err, "Client.Timeout exceeded")
+}
+
+func TestNewHTTPClient_InvalidCABundle(t *testing.T) {
+	_, err := NewHTTPClient(ClientArgs{CABundle: []byte("not a certificate")})
+	assert.EqualError(t, err, "no PEM encoded certificates found in the CA bundle")
+}
@@ -0,0 +1,89 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// DefaultCreatedAtLayout is the layout of the Google Forms "Timestamp" column, in the en_US locale
+const DefaultCreatedAtLayout = "1/2/2006 15:04:05"
+
+// errNotDateCell is returned for a created-at cell holding a number which isn't formatted as a date
+var errNotDateCell = errors.New("the cell isn't formatted as a date or a date-time")
+
+// resolveCreatedAtLocation returns the time zone of the created-at cells without zone, the configured location if set,
+// otherwise the spreadsheet time zone, UTC if the spreadsheet time zone is unknown
+func resolveCreatedAtLocation(location *time.Location, spreadsheetTimeZone string) (*time.Location, error) {
+	if location != nil {
+		return location, nil
+	}
+	return spreadsheetLocation(spreadsheetTimeZone)
+}
+
+// spreadsheetLocation returns the location of the spreadsheet time zone, UTC if the time zone is unknown
+func spreadsheetLocation(spreadsheetTimeZone string) (*time.Location, error) {
+	if spreadsheetTimeZone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(spreadsheetTimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("error loading the spreadsheet time zone %q: %w", spreadsheetTimeZone, err)
+	}
+	return location, nil
+}
+
+// createdAt returns the time of the created-at cell value, false if the cell is blank.
+// A string is parsed with the created-at layout, in the created-at location unless it holds a zone.
+// A date-time serial number, or a time.Time converted from one with typedValues, is a wall clock time
+// in the created-at location, the cell being formatted as a date or a date-time, so a number isn't taken for a date.
+func (b *BatchReader) createdAt(value any, cell *sheets.CellData) (time.Time, bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, false, nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return time.Time{}, false, nil
+		}
+		t, err := time.ParseInLocation(b.createdAtLayout, strings.TrimSpace(v), b.createdAtLocation)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		return t, true, nil
+	case time.Time:
+		if !isDateFormat(cell) {
+			return time.Time{}, false, errNotDateCell
+		}
+		return time.Date(v.Year(), v.Month(), v.Day(),
+			v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), b.createdAtLocation), true, nil
+	case float64:
+		if !isDateFormat(cell) {
+			return time.Time{}, false, errNotDateCell
+		}
+		return serialToTime(v, b.createdAtLocation), true, nil
+	case int64:
+		if !isDateFormat(cell) {
+			return time.Time{}, false, errNotDateCell
+		}
+		return serialToTime(float64(v), b.createdAtLocation), true, nil
+	default:
+		return time.Time{}, false, fmt.Errorf("unsupported value type %T", value)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestResolveCreatedAtLocation(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	assert.NoError(t, err)
+
+	got, err := resolveCreatedAtLocation(paris, "America/New_York")
+	assert.NoError(t, err)
+	assert.Equal(t, paris, got)
+
+	got, err = resolveCreatedAtLocation(nil, "America/New_York")
+	assert.NoError(t, err)
+	assert.Equal(t, "America/New_York", got.String())
+
+	got, err = resolveCreatedAtLocation(nil, "")
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, got)
+
+	_, err = resolveCreatedAtLocation(nil, "Mars/Olympus")
+	assert.EqualError(t, err, `error loading the spreadsheet time zone "Mars/Olympus": unknown time zone Mars/Olympus`)
+}
+
+func TestBatchReader_createdAt(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	br := &BatchReader{createdAtLayout: DefaultCreatedAtLayout, createdAtLocation: newYork}
+	formatted := func(numberFormat string) *sheets.CellData {
+		return &sheets.CellData{EffectiveFormat: &sheets.CellFormat{NumberFormat: &sheets.NumberFormat{Type: numberFormat}}}
+	}
+
+	tests := []struct {
+		name  string
+		value any
+		cell  *sheets.CellData
+		want  time.Time
+		ok    bool
+		err   string
+	}{{
+		name:  "forms timestamp in the spreadsheet time zone",
+		value: "10/16/2026 14:03:22",
+		want:  time.Date(2026, 10, 16, 14, 3, 22, 0, newYork),
+		ok:    true,
+	}, {
+		name:  "serial number",
+		value: float64(46311.5),
+		cell:  formatted("DATE_TIME"),
+		want:  time.Date(2026, 10, 16, 12, 0, 0, 0, newYork),
+		ok:    true,
+	}, {
+		name:  "integral serial number",
+		value: int64(46311),
+		cell:  formatted("DATE"),
+		want:  time.Date(2026, 10, 16, 0, 0, 0, 0, newYork),
+		ok:    true,
+	}, {
+		name:  "typed value",
+		value: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		cell:  formatted("DATE_TIME"),
+		want:  time.Date(2026, 10, 16, 12, 0, 0, 0, newYork),
+		ok:    true,
+	}, {
+		name:  "number",
+		value: int64(46311),
+		cell:  formatted("NUMBER"),
+		err:   "the cell isn't formatted as a date or a date-time",
+	}, {
+		name:  "number without cell data",
+		value: float64(46311.5),
+		err:   "the cell isn't formatted as a date or a date-time",
+	}, {
+		name:  "typed time",
+		value: time.Date(1899, 12, 30, 12, 0, 0, 0, time.UTC),
+		cell:  formatted("TIME"),
+		err:   "the cell isn't formatted as a date or a date-time",
+	}, {
+		name:  "blank cell",
+		value: " ",
+	}, {
+		name:  "invalid value",
+		value: "yesterday",
+		err:   `parsing time "yesterday" as "1/2/2006 15:04:05": cannot parse "yesterday" as "1"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := br.createdAt(tt.value, tt.cell)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.ok, ok)
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+		})
+	}
+
+	// a zone in the cell takes precedence over the created-at location
+	br.createdAtLayout = time.RFC3339
+	got, ok, err := br.createdAt("2026-10-16T14:03:22+02:00", nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, time.Date(2026, 10, 16, 12, 3, 22, 0, time.UTC).Equal(got))
+}
+
+func TestBatchReader_valueRangesToRecords_CreatedAtColumn(t *testing.T) {
+	br := &BatchReader{
+		sheetTitle:        "Form Responses 1",
+		headerRow:         1,
+		createdAtColumn:   "Timestamp",
+		createdAtLayout:   DefaultCreatedAtLayout,
+		createdAtLocation: time.UTC,
+	}
+	in := []*sheets.MatchedValueRange{
+		{ValueRange: &sheets.ValueRange{Values: [][]any{{"Timestamp", "Email"}}}},
+		{ValueRange: &sheets.ValueRange{Values: [][]any{
+			{"10/16/2026 14:03:22", "jane@example.com"},
+			{"", "john@example.com"},
+		}}},
+	}
+
+	records, _, err := br.valueRangesToRecords(in, nil, 1)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	createdAt, err := records[0].Metadata.GetCreatedAt()
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2026, 10, 16, 14, 3, 22, 0, time.UTC).Equal(createdAt))
+	// the fetch time is used for a blank cell
+	createdAt, err = records[1].Metadata.GetCreatedAt()
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), createdAt, time.Minute)
+
+	in[1].ValueRange.Values[1][0] = "yesterday"
+	_, _, err = br.valueRangesToRecords(in, nil, 1)
+	assert.ErrorContains(t, err, `row 3 has an invalid created-at value "yesterday"`)
+
+	// the unformatted serial numbers are dates with the number format of their cells
+	in[1].ValueRange.Values = [][]any{{float64(46311.5), "jane@example.com"}}
+	rowData := []*sheets.RowData{{Values: []*sheets.CellData{
+		{EffectiveFormat: &sheets.CellFormat{NumberFormat: &sheets.NumberFormat{Type: "DATE_TIME"}}},
+	}}}
+	records, _, err = br.valueRangesToRecords(in, rowData, 1)
+	assert.NoError(t, err)
+	createdAt, err = records[0].Metadata.GetCreatedAt()
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC).Equal(createdAt))
+
+	rowData[0].Values[0].EffectiveFormat.NumberFormat.Type = "NUMBER"
+	_, _, err = br.valueRangesToRecords(in, rowData, 1)
+	assert.ErrorContains(t, err, `row 2 has an invalid created-at value "46311.5": the cell isn't formatted as a date or a date-time`)
+}
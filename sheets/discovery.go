@@ -0,0 +1,96 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"regexp"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// gridSheetType is the type of the sheets(tabs) holding cells, as opposed to the sheets holding a chart
+const gridSheetType = "GRID"
+
+// SheetsDiscovery is the result of a discovery of the sheets(tabs) of the spreadsheet, to be applied with SetSheets
+type SheetsDiscovery struct {
+	spreadsheet *sheets.Spreadsheet
+	// sheets are the properties of the sheets to read, in the order of the tabs
+	sheets []*sheets.SheetProperties
+}
+
+// DiscoverSheets returns the grid sheets(tabs) of the spreadsheet whose title matches the include pattern, if any,
+// and doesn't match the exclude pattern, if any.
+// It only reads the immutable fields of the reader, so it can run alongside GetSheetRecords.
+func (m *MultiReader) DiscoverSheets(ctx context.Context) (*SheetsDiscovery, error) {
+	spreadsheet, err := getSpreadsheet(ctx, m.sheetSvc, m.spreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+	return discoverSheets(spreadsheet, m.includeSheets, m.excludeSheets), nil
+}
+
+// discoverSheets returns the grid sheets of the spreadsheet matching the include and exclude patterns
+func discoverSheets(spreadsheet *sheets.Spreadsheet, include, exclude *regexp.Regexp) *SheetsDiscovery {
+	discovery := &SheetsDiscovery{spreadsheet: spreadsheet}
+	for _, sheet := range spreadsheet.Sheets {
+		properties := sheet.Properties
+		if properties == nil || (properties.SheetType != "" && properties.SheetType != gridSheetType) {
+			continue
+		}
+		if include != nil && !include.MatchString(properties.Title) {
+			continue
+		}
+		if exclude != nil && exclude.MatchString(properties.Title) {
+			continue
+		}
+		discovery.sheets = append(discovery.sheets, properties)
+	}
+	return discovery
+}
+
+// SetSheets replaces the sheets read with the discovered ones, returning the gids of the sheets not read anymore.
+// The readers of the sheets already read are kept, along with the last known content of their rows,
+// only their title and row count are updated in case the sheet was renamed or resized.
+func (m *MultiReader) SetSheets(discovery *SheetsDiscovery) ([]int64, error) {
+	current := make(map[int64]*BatchReader, len(m.readers))
+	for _, reader := range m.readers {
+		current[reader.sheetID] = reader
+	}
+
+	readers := make([]*BatchReader, 0, len(discovery.sheets))
+	for _, properties := range discovery.sheets {
+		reader, ok := current[properties.SheetId]
+		if ok {
+			reader.sheetTitle = properties.Title
+			reader.rowCount = gridRowCount(properties)
+			delete(current, properties.SheetId)
+		} else {
+			var err error
+			reader, err = newBatchReader(m.sheetSvc, discovery.spreadsheet, properties, m.readerArgs)
+			if err != nil {
+				return nil, err
+			}
+		}
+		readers = append(readers, reader)
+	}
+	m.readers = readers
+
+	removed := make([]int64, 0, len(current))
+	for sheetID := range current {
+		removed = append(removed, sheetID)
+	}
+	return removed, nil
+}
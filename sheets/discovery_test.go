@@ -0,0 +1,88 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/state"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestDiscoverSheets(t *testing.T) {
+	spreadsheet := &sheets.Spreadsheet{Sheets: []*sheets.Sheet{
+		{Properties: &sheets.SheetProperties{SheetId: 0, Title: "2024-01"}},
+		{Properties: &sheets.SheetProperties{SheetId: 1, Title: "2024-02", SheetType: "GRID"}},
+		{Properties: &sheets.SheetProperties{SheetId: 2, Title: "2024-chart", SheetType: "OBJECT"}},
+		{Properties: &sheets.SheetProperties{SheetId: 3, Title: "_2024-scratch"}},
+		{Properties: &sheets.SheetProperties{SheetId: 4, Title: "Summary"}},
+	}}
+
+	tests := []struct {
+		name    string
+		include *regexp.Regexp
+		exclude *regexp.Regexp
+		want    []int64
+	}{{
+		name: "all grid sheets",
+		want: []int64{0, 1, 3, 4},
+	}, {
+		name:    "include pattern",
+		include: regexp.MustCompile(`2024-`),
+		want:    []int64{0, 1, 3},
+	}, {
+		name:    "include and exclude patterns",
+		include: regexp.MustCompile(`2024-`),
+		exclude: regexp.MustCompile(`^_`),
+		want:    []int64{0, 1},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discovery := discoverSheets(spreadsheet, tt.include, tt.exclude)
+			got := make([]int64, 0, len(discovery.sheets))
+			for _, sheet := range discovery.sheets {
+				got = append(got, sheet.SheetId)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMultiReader_SetSheets(t *testing.T) {
+	orders := &BatchReader{sheetID: 0, sheetTitle: "Orders"}
+	orders.SetRowStates(map[int64]state.Row{1: state.NewRow([]byte(`["a"]`))})
+	m := &MultiReader{readers: []*BatchReader{
+		orders,
+		{sheetID: 1234, sheetTitle: "Returns"},
+	}}
+
+	removed, err := m.SetSheets(&SheetsDiscovery{
+		spreadsheet: &sheets.Spreadsheet{},
+		sheets: []*sheets.SheetProperties{
+			{SheetId: 42, Title: "Refunds"},
+			{SheetId: 0, Title: "All orders"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1234}, removed)
+	assert.Equal(t, []int64{42, 0}, m.SheetIDs())
+	// the reader of a known sheet is kept, with its row states, and renamed
+	assert.Same(t, orders, m.readers[1])
+	assert.Equal(t, "All orders", orders.sheetTitle)
+	assert.Len(t, orders.RowStates(), 1)
+	assert.Equal(t, "Refunds", m.readers[0].sheetTitle)
+}
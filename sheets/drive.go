@@ -0,0 +1,105 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+const (
+	// spreadsheetMimeType is the Drive MIME type of the Google Sheets spreadsheets
+	spreadsheetMimeType = "application/vnd.google-apps.spreadsheet"
+	// driveFilesFields is the partial response field mask of the folder listing
+	driveFilesFields = "nextPageToken,files(id,name)"
+	// driveEndpointPath is the path of the Drive API under the ClientArgs.Endpoint
+	driveEndpointPath = "drive/v3/"
+)
+
+// DriveFile is a spreadsheet file of a Drive folder
+type DriveFile struct {
+	ID   string
+	Name string
+}
+
+// DriveFolderArgs are the options of the DriveFolder
+type DriveFolderArgs struct {
+	ClientArgs
+	FolderID string
+}
+
+// DriveFolder lists the spreadsheets of a Drive folder, in a "My Drive" or a shared drive
+type DriveFolder struct {
+	folderID string
+	driveSvc *drive.Service
+}
+
+func NewDriveFolder(ctx context.Context, args DriveFolderArgs) (*DriveFolder, error) {
+	client, err := newAuthClient(ctx, args.ClientArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []option.ClientOption{option.WithHTTPClient(client)}
+	if args.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(strings.TrimSuffix(args.Endpoint, "/")+"/"+driveEndpointPath))
+	}
+
+	driveService, err := drive.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating drive service client: %w", err)
+	}
+
+	return &DriveFolder{
+		folderID: args.FolderID,
+		driveSvc: driveService,
+	}, nil
+}
+
+// FolderID returns the ID of the listed folder
+func (f *DriveFolder) FolderID() string {
+	return f.folderID
+}
+
+// Spreadsheets returns the spreadsheets of the folder, in the order of their creation,
+// the trashed files and the subfolders' content are left out
+func (f *DriveFolder) Spreadsheets(ctx context.Context) ([]DriveFile, error) {
+	query := fmt.Sprintf("'%s' in parents and mimeType = '%s' and trashed = false", f.folderID, spreadsheetMimeType)
+
+	var files []DriveFile
+	err := f.driveSvc.Files.List().
+		Q(query).
+		Fields(driveFilesFields).
+		OrderBy("createdTime").
+		// the folder may be in a shared drive
+		Corpora("allDrives").
+		SupportsAllDrives(true).
+		IncludeItemsFromAllDrives(true).
+		Context(ctx).
+		Pages(ctx, func(list *drive.FileList) error {
+			for _, file := range list.Files {
+				files = append(files, DriveFile{ID: file.Id, Name: file.Name})
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("error listing the spreadsheets of drive folder(%s): %w", f.folderID, err)
+	}
+	return files, nil
+}
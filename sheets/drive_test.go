@@ -0,0 +1,83 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestDriveFolder_Spreadsheets(t *testing.T) {
+	pages := map[string]string{
+		"":      `{"nextPageToken":"page2","files":[{"id":"file1","name":"2024-01 Expenses"}]}`,
+		"page2": `{"files":[{"id":"file2","name":"2024-02 Expenses"}]}`,
+	}
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "/drive/v3/files", r.URL.Path)
+		assert.Equal(t,
+			"'folder1' in parents and mimeType = 'application/vnd.google-apps.spreadsheet' and trashed = false",
+			query.Get("q"))
+		assert.Equal(t, "allDrives", query.Get("corpora"))
+		assert.Equal(t, "true", query.Get("supportsAllDrives"))
+		assert.Equal(t, "true", query.Get("includeItemsFromAllDrives"))
+		assert.Equal(t, "createdTime", query.Get("orderBy"))
+		assert.Equal(t, "Bearer dummy", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(pages[query.Get("pageToken")]))
+	}))
+	defer testServer.Close()
+
+	folder, err := NewDriveFolder(context.Background(), DriveFolderArgs{
+		ClientArgs: ClientArgs{
+			TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+			Endpoint:    testServer.URL,
+		},
+		FolderID: "folder1",
+	})
+	assert.NoError(t, err)
+
+	files, err := folder.Spreadsheets(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []DriveFile{
+		{ID: "file1", Name: "2024-01 Expenses"},
+		{ID: "file2", Name: "2024-02 Expenses"},
+	}, files)
+}
+
+func TestDriveFolder_Spreadsheets_Error(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":404,"message":"File not found: folder1."}}`))
+	}))
+	defer testServer.Close()
+
+	folder, err := NewDriveFolder(context.Background(), DriveFolderArgs{
+		ClientArgs: ClientArgs{
+			TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+			Endpoint:    testServer.URL,
+		},
+		FolderID: "folder1",
+	})
+	assert.NoError(t, err)
+
+	_, err = folder.Spreadsheets(context.Background())
+	assert.ErrorContains(t, err, "error listing the spreadsheets of drive folder(folder1)")
+	assert.ErrorContains(t, err, "File not found: folder1.")
+}
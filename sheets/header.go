@@ -0,0 +1,146 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// columnLettersRegexp matches a column in A1 notation, e.g. C or AB
+var columnLettersRegexp = regexp.MustCompile(`^[A-Z]{1,3}$`)
+
+// columnIndex returns the A1 notation letters as a 0-based column index, e.g. A => 0, AA => 26
+func columnIndex(letters string) int {
+	index := 0
+	for _, letter := range letters {
+		index = index*26 + int(letter-'A') + 1
+	}
+	return index - 1
+}
+
+// resolveColumn returns the 0-based index of the column in the rows of the cell range, referenced either by its header name,
+// or by its letters in A1 notation, the header names taking precedence
+func resolveColumn(column string, headers []string, cellRange CellRange) (int, error) {
+	for i, header := range headers {
+		if header == column {
+			return i, nil
+		}
+	}
+	if !columnLettersRegexp.MatchString(column) {
+		return 0, fmt.Errorf("column %q not found in the header row, and isn't a column letter", column)
+	}
+	index := int64(columnIndex(column))
+	if index < cellRange.StartColumn || (cellRange.EndColumn > 0 && index >= cellRange.EndColumn) {
+		return 0, fmt.Errorf("column %q is outside the range", column)
+	}
+	return int(index - cellRange.StartColumn), nil
+}
+
+// columnName returns the A1 notation letters of the 0-based column index, e.g. 0 => A, 26 => AA
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// headerNames returns the field names from the header row cells, the first cell being in the 0-based firstColumn.
+// A blank header cell is named after its column letter, e.g. "C", and a duplicate name
+// gets the suffix "_<n>" where n is its occurrence count, e.g. "name", "name_2", "name_3".
+// A suffixed name never clashes with another header cell, the count being incremented until the name is unused,
+// e.g. "a", "a", "a_2" are named "a", "a_3", "a_2".
+func headerNames(headerRow []any, firstColumn int64) []string {
+	cells := make([]string, len(headerRow))
+	// used holds the names of the header cells, reserved for them, and the names already given
+	used := make(map[string]bool, len(headerRow))
+	for i, cell := range headerRow {
+		if cell != nil {
+			cells[i] = strings.TrimSpace(fmt.Sprint(cell))
+		}
+		if cells[i] != "" {
+			used[cells[i]] = true
+		}
+	}
+
+	names := make([]string, len(headerRow))
+	given := make(map[string]bool, len(headerRow))
+	seen := make(map[string]int, len(headerRow))
+	for i, name := range cells {
+		if name == "" {
+			name = columnName(int(firstColumn) + i)
+		}
+		seen[name]++
+		if given[name] {
+			name = suffixedName(name, seen[name], used)
+		}
+		given[name] = true
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// uniqueName returns the name if it's unused, or else the name with the first unused "_<n>" suffix from 2,
+// marking the returned name as used
+func uniqueName(name string, used map[string]bool) string {
+	if !used[name] {
+		used[name] = true
+		return name
+	}
+	return suffixedName(name, 2, used)
+}
+
+// suffixedName returns the name with the first unused "_<n>" suffix from n, marking the returned name as used
+func suffixedName(name string, n int, used map[string]bool) string {
+	for ; ; n++ {
+		candidate := fmt.Sprintf("%s_%d", name, n)
+		if !used[candidate] {
+			used[candidate] = true
+			return candidate
+		}
+	}
+}
+
+// rowToStructuredData maps the row cells to the header names, cells in columns without a header
+// are named after their column letter, suffixed like the duplicate header names if a header has the same name,
+// the first cell being in the 0-based firstColumn, header fields missing in the row are set to nil
+func rowToStructuredData(headers []string, row []any, firstColumn int64) opencdc.StructuredData {
+	data := make(opencdc.StructuredData, max(len(headers), len(row)))
+	var used map[string]bool
+	if len(row) > len(headers) {
+		used = make(map[string]bool, len(row))
+		for _, header := range headers {
+			used[header] = true
+		}
+	}
+	for i := range max(len(headers), len(row)) {
+		var value any
+		if i < len(row) {
+			value = row[i]
+		}
+		if i < len(headers) {
+			data[headers[i]] = value
+		} else {
+			data[uniqueName(columnName(int(firstColumn)+i), used)] = value
+		}
+	}
+	return data
+}
@@ -0,0 +1,87 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for index, want := range tests {
+		assert.Equal(t, want, columnName(index))
+		assert.Equal(t, index, columnIndex(want))
+	}
+}
+
+func TestHeaderNames(t *testing.T) {
+	got := headerNames([]any{"id", " name ", "", nil, "name", "name", "C"}, 0)
+	assert.Equal(t, []string{"id", "name", "C", "D", "name_2", "name_3", "C_2"}, got)
+
+	// the blank header cells are named after their column in the sheet, the range starting at column B
+	got = headerNames([]any{"id", ""}, 1)
+	assert.Equal(t, []string{"id", "C"}, got)
+
+	// the suffixed names don't clash with the header cells
+	got = headerNames([]any{"a", "a", "a_2"}, 0)
+	assert.Equal(t, []string{"a", "a_3", "a_2"}, got)
+
+	got = headerNames([]any{"C", "", ""}, 0)
+	assert.Equal(t, []string{"C", "B", "C_2"}, got)
+}
+
+func TestRowToStructuredData(t *testing.T) {
+	// the cell after the last header is named after its column letter, suffixed as the header "C" is used
+	got := rowToStructuredData([]string{"id", "C"}, []any{"1", "x", "y"}, 0)
+	assert.Equal(t, opencdc.StructuredData{"id": "1", "C": "x", "C_2": "y"}, got)
+
+	// the header fields missing in the row are set to nil
+	got = rowToStructuredData([]string{"id", "name"}, []any{"1"}, 0)
+	assert.Equal(t, opencdc.StructuredData{"id": "1", "name": nil}, got)
+}
+
+func TestResolveColumn(t *testing.T) {
+	headers := []string{"id", "name", "B"}
+
+	index, err := resolveColumn("name", headers, CellRange{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, index)
+
+	// header names take precedence over column letters
+	index, err = resolveColumn("B", headers, CellRange{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, index)
+
+	index, err = resolveColumn("AB", nil, CellRange{})
+	assert.NoError(t, err)
+	assert.Equal(t, 27, index)
+
+	_, err = resolveColumn("email", headers, CellRange{})
+	assert.EqualError(t, err, `column "email" not found in the header row, and isn't a column letter`)
+
+	// the column letters are relative to the first column of the range B:F
+	index, err = resolveColumn("D", nil, CellRange{StartColumn: 1, EndColumn: 6})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, index)
+
+	_, err = resolveColumn("A", nil, CellRange{StartColumn: 1, EndColumn: 6})
+	assert.EqualError(t, err, `column "A" is outside the range`)
+
+	_, err = resolveColumn("G", nil, CellRange{StartColumn: 1, EndColumn: 6})
+	assert.EqualError(t, err, `column "G" is outside the range`)
+}
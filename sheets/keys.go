@@ -0,0 +1,63 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// keyColumn is a key column resolved to its index, and the field name of its value in the record key
+type keyColumn struct {
+	index int
+	name  string
+}
+
+// resolveKeyColumns resolves the key columns, referenced by header name or column letter, using the current header names.
+// The key fields are named after the header names, or the column letters when there's no header.
+func (b *BatchReader) resolveKeyColumns(headers []string) ([]keyColumn, error) {
+	keyColumns := make([]keyColumn, 0, len(b.keyColumns))
+	for _, column := range b.keyColumns {
+		index, err := resolveColumn(column, headers, b.cellRange)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key column: %w", err)
+		}
+		name := columnName(int(b.cellRange.StartColumn) + index)
+		if index < len(headers) {
+			name = headers[index]
+		}
+		keyColumns = append(keyColumns, keyColumn{index: index, name: name})
+	}
+	return keyColumns, nil
+}
+
+// rowKey returns the record key of the row, holding the values of the key columns, nil if any key cell is blank.
+// A partial composite key isn't an identity, two half-filled rows could share it.
+func rowKey(keyColumns []keyColumn, row []any) opencdc.StructuredData {
+	key := make(opencdc.StructuredData, len(keyColumns))
+	for _, column := range keyColumns {
+		var value any
+		if column.index < len(row) {
+			value = row[column.index]
+		}
+		if value == nil || strings.TrimSpace(fmt.Sprint(value)) == "" {
+			return nil
+		}
+		key[column.name] = value
+	}
+	return key
+}
@@ -0,0 +1,108 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestBatchReader_valueRangesToRecords_KeyColumns(t *testing.T) {
+	in := []*sheets.MatchedValueRange{
+		{ValueRange: &sheets.ValueRange{Values: [][]any{{"region", "id", "name"}}}},
+		{ValueRange: &sheets.ValueRange{Values: [][]any{
+			{"emea", "1", "alice"},
+			{"", "", "no key"},
+			{"apac", "", "partial key"},
+		}}},
+	}
+
+	br := &BatchReader{
+		spreadsheetID: "dummy_spreadsheet",
+		sheetID:       1234,
+		headerRow:     1,
+		keyColumns:    []string{"region", "B"},
+		skipEmptyKeys: true,
+	}
+	out, page, err := br.valueRangesToRecords(in, nil, 1)
+	assert.NoError(t, err)
+	assert.Len(t, out, 1)
+	assert.Equal(t, opencdc.StructuredData{"region": "emea", "id": "1"}, out[0].Key)
+	assert.Equal(t, opencdc.Position(`{"row_offset":2,"spreadsheet_id":"dummy_spreadsheet","sheet_id":1234}`), out[0].Position)
+	// the rows are identified by their key
+	assert.Equal(t, map[int64]string{2: string(out[0].Key.Bytes())}, page.identities)
+
+	br.skipEmptyKeys = false
+	_, _, err = br.valueRangesToRecords(in, nil, 1)
+	assert.EqualError(t, err, "row 3 has an empty key, key columns: region,B")
+
+	// a partial composite key is an empty key too
+	_, _, err = br.valueRangesToRecords([]*sheets.MatchedValueRange{in[0], {ValueRange: &sheets.ValueRange{Values: [][]any{
+		{"apac", "", "partial key"},
+	}}}}, nil, 1)
+	assert.EqualError(t, err, "row 2 has an empty key, key columns: region,B")
+
+	br.keyColumns = []string{"email"}
+	_, _, err = br.valueRangesToRecords(in, nil, 1)
+	assert.EqualError(t, err, `invalid key column: column "email" not found in the header row, and isn't a column letter`)
+}
+
+func TestBatchReader_valueRangesToRecords_KeyColumnLetters(t *testing.T) {
+	in := []*sheets.MatchedValueRange{{ValueRange: &sheets.ValueRange{Values: [][]any{{"a", "1"}}}}}
+
+	br := &BatchReader{keyColumns: []string{"B"}}
+	out, _, err := br.valueRangesToRecords(in, nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, opencdc.StructuredData{"B": "1"}, out[0].Key)
+}
+
+func TestBatchReader_pageRecords_HoldEmptyKeys(t *testing.T) {
+	in := []*sheets.MatchedValueRange{
+		{ValueRange: &sheets.ValueRange{Values: [][]any{{"id", "name"}}}},
+		{ValueRange: &sheets.ValueRange{Values: [][]any{
+			{"1", "alice"},
+			{"", "typing"},
+			{"3", "carol"},
+		}}},
+	}
+
+	br := &BatchReader{
+		spreadsheetID: "dummy_spreadsheet",
+		sheetID:       1234,
+		headerRow:     1,
+		keyColumns:    []string{"id"},
+		holdEmptyKeys: true,
+		batchSize:     3,
+		rowCount:      1000,
+	}
+	// the rows from the row with a blank key are read again on the next poll
+	out, next, err := br.pageRecords(in, nil, 1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, out, 1)
+	assert.Equal(t, opencdc.StructuredData{"id": "1"}, out[0].Key)
+	assert.Equal(t, int64(3), next.heldRow)
+	assert.Zero(t, next.pageEnd)
+
+	// the key is filled
+	in[1].ValueRange.Values[1][0] = "2"
+	out, next, err = br.pageRecords(in, nil, 1, 2)
+	assert.NoError(t, err)
+	assert.Len(t, out, 3)
+	assert.Zero(t, next.heldRow)
+	assert.Equal(t, int64(4), next.pageEnd)
+}
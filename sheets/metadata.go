@@ -0,0 +1,110 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// spreadsheetFields is the partial response field mask used to fetch only the spreadsheet title and time zone,
+// the sheets(tabs) properties and row counts, and the sheets of the named ranges
+const spreadsheetFields = "properties(title,timeZone),sheets.properties(sheetId,title,index,sheetType,gridProperties.rowCount)," +
+	"namedRanges(name,range.sheetId)"
+
+// resolveSheet returns the spreadsheet metadata, and the properties of the sheet(tab) matching the gid and/or the title.
+// A negative sheetID means the gid is unknown, the first sheet is used when neither gid nor title is set.
+func resolveSheet(
+	ctx context.Context,
+	svc *sheets.Service,
+	spreadsheetID string,
+	sheetID int64,
+	sheetName string,
+) (*sheets.Spreadsheet, *sheets.SheetProperties, error) {
+	spreadsheet, err := getSpreadsheet(ctx, svc, spreadsheetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sheet, err := findSheet(spreadsheet, spreadsheetID, sheetID, sheetName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return spreadsheet, sheet, nil
+}
+
+// getSpreadsheet returns the spreadsheet title and time zone, and the properties of its sheets(tabs)
+func getSpreadsheet(ctx context.Context, svc *sheets.Service, spreadsheetID string) (*sheets.Spreadsheet, error) {
+	spreadsheet, err := svc.Spreadsheets.Get(spreadsheetID).Fields(spreadsheetFields).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("error getting spreadsheet(%s) metadata: %w", spreadsheetID, err)
+	}
+	return spreadsheet, nil
+}
+
+// findNamedRangeSheet returns the properties of the sheet(tab) of the named range in the spreadsheet metadata
+func findNamedRangeSheet(spreadsheet *sheets.Spreadsheet, spreadsheetID, namedRange string) (*sheets.SheetProperties, error) {
+	for _, named := range spreadsheet.NamedRanges {
+		if named.Name != namedRange || named.Range == nil {
+			continue
+		}
+		return findSheet(spreadsheet, spreadsheetID, named.Range.SheetId, "")
+	}
+	return nil, fmt.Errorf("named range(%q) not found in spreadsheet(%s)", namedRange, spreadsheetID)
+}
+
+// findSheet returns the properties of the sheet(tab) matching the gid and/or the title in the spreadsheet metadata
+func findSheet(spreadsheet *sheets.Spreadsheet, spreadsheetID string, sheetID int64, sheetName string) (*sheets.SheetProperties, error) {
+	var byID, byName *sheets.SheetProperties
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties == nil {
+			continue
+		}
+		if sheetID >= 0 && sheet.Properties.SheetId == sheetID {
+			byID = sheet.Properties
+		}
+		if sheetName != "" && sheet.Properties.Title == sheetName {
+			byName = sheet.Properties
+		}
+	}
+
+	switch {
+	case sheetID >= 0 && byID == nil:
+		return nil, fmt.Errorf("sheet(gid:%d) not found in spreadsheet(%s)", sheetID, spreadsheetID)
+	case sheetName != "" && byName == nil:
+		return nil, fmt.Errorf("sheet(%q) not found in spreadsheet(%s)", sheetName, spreadsheetID)
+	case byID != nil && byName != nil && byID != byName:
+		return nil, fmt.Errorf("sheet(gid:%d) is named %q, which doesn't match the sheet name %q", sheetID, byID.Title, sheetName)
+	case byID != nil:
+		return byID, nil
+	case byName != nil:
+		return byName, nil
+	case len(spreadsheet.Sheets) == 0 || spreadsheet.Sheets[0].Properties == nil:
+		return nil, fmt.Errorf("no sheets found in spreadsheet(%s)", spreadsheetID)
+	default:
+		// sheets are returned in the order of the tabs
+		return spreadsheet.Sheets[0].Properties, nil
+	}
+}
+
+// gridRowCount returns the number of rows of the sheet grid, 0 if unknown
+func gridRowCount(properties *sheets.SheetProperties) int64 {
+	if properties.GridProperties == nil {
+		return 0
+	}
+	return properties.GridProperties.RowCount
+}
@@ -0,0 +1,113 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// spreadsheetQuery is the query string of the spreadsheet metadata request
+var spreadsheetQuery = "alt=json&fields=" + url.QueryEscape(spreadsheetFields) + "&prettyPrint=false"
+
+// newMetadataServer returns a test server answering the spreadsheet metadata request with a single sheet(tab)
+func newMetadataServer(t *testing.T, sheetID int64, sheetTitle string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(&testHandler{
+		t:          t,
+		url:        &url.URL{Path: "/v4/spreadsheets/dummy_spreadsheet", RawQuery: spreadsheetQuery},
+		statusCode: 200,
+		resp: []byte(fmt.Sprintf(`{"properties":{"title":"Dummy"},"sheets":[{"properties":{"sheetId":%d,"title":%q}}]}`,
+			sheetID, sheetTitle)),
+		header: http.Header{},
+	})
+}
+
+func TestResolveSheet(t *testing.T) {
+	th := &testHandler{
+		t:          t,
+		url:        &url.URL{Path: "/v4/spreadsheets/dummy_spreadsheet", RawQuery: spreadsheetQuery},
+		statusCode: 200,
+		resp: []byte(`{"properties":{"title":"Dummy"},"sheets":[
+			{"properties":{"sheetId":0,"title":"Sheet1","index":0}},
+			{"properties":{"sheetId":1234,"title":"Orders","index":1}}
+		]}`),
+		header: http.Header{},
+	}
+	testServer := httptest.NewServer(th)
+	defer testServer.Close()
+	sheetSvc, err := sheets.NewService(
+		context.Background(),
+		option.WithEndpoint(testServer.URL),
+		option.WithHTTPClient(&http.Client{}))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		sheetID   int64
+		sheetName string
+		want      *sheets.SheetProperties
+		err       string
+	}{{
+		name:    "first sheet when neither gid nor name is set",
+		sheetID: -1,
+		want:    &sheets.SheetProperties{SheetId: 0, Title: "Sheet1", Index: 0},
+	}, {
+		name:      "gid resolved from name",
+		sheetID:   -1,
+		sheetName: "Orders",
+		want:      &sheets.SheetProperties{SheetId: 1234, Title: "Orders", Index: 1},
+	}, {
+		name:    "name resolved from gid",
+		sheetID: 1234,
+		want:    &sheets.SheetProperties{SheetId: 1234, Title: "Orders", Index: 1},
+	}, {
+		name:      "gid and name mismatch",
+		sheetID:   0,
+		sheetName: "Orders",
+		err:       `sheet(gid:0) is named "Sheet1", which doesn't match the sheet name "Orders"`,
+	}, {
+		name:    "unknown gid",
+		sheetID: 42,
+		err:     "sheet(gid:42) not found in spreadsheet(dummy_spreadsheet)",
+	}, {
+		name:      "unknown name",
+		sheetID:   -1,
+		sheetName: "Missing",
+		err:       `sheet("Missing") not found in spreadsheet(dummy_spreadsheet)`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spreadsheet, got, err := resolveSheet(context.Background(), sheetSvc, "dummy_spreadsheet", tt.sheetID, tt.sheetName)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "Dummy", spreadsheet.Properties.Title)
+			assert.Equal(t, tt.want.SheetId, got.SheetId)
+			assert.Equal(t, tt.want.Title, got.Title)
+			assert.Equal(t, tt.want.Index, got.Index)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/position"
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/state"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"google.golang.org/api/sheets/v4"
+)
+
+// SheetLocator locates a sheet(tab) of the spreadsheet by its gid, or by its title when the gid is negative
+type SheetLocator struct {
+	ID   int64
+	Name string
+}
+
+type MultiReaderArgs struct {
+	// BatchReaderArgs are the options shared by the readers of all the sheets
+	BatchReaderArgs
+	// Sheets are the sheets(tabs) to read, the sheet located by the BatchReaderArgs is read when empty
+	Sheets []SheetLocator
+	// AllSheets reads all the grid sheets(tabs) of the spreadsheet whose title matches the IncludeSheets pattern,
+	// if set, and doesn't match the ExcludeSheets pattern, if set, instead of the Sheets
+	AllSheets     bool
+	IncludeSheets *regexp.Regexp
+	ExcludeSheets *regexp.Regexp
+	// SheetsRefreshPeriod is the period of the discovery of the sheets with AllSheets, run by the iterator
+	SheetsRefreshPeriod time.Duration
+	// Snapshot are the options of the parallel snapshot of the sheets, run by the iterator on start
+	Snapshot SnapshotArgs
+}
+
+// MultiReader reads several sheets(tabs) of a spreadsheet, fetching the values of all the sheets
+// with a single BatchGetByDataFilter request per poll, each sheet being converted to records by its BatchReader
+type MultiReader struct {
+	// spreadsheet ID of the Google sheet
+	spreadsheetID string
+	// instance of sheets service, shared with the readers of the sheets
+	sheetSvc *sheets.Service
+	backoff
+	// readers are the readers of the sheets, in the order of the sheets in the args
+	readers              []*BatchReader
+	dateTimeRenderOption string
+	valueRenderOption    string
+	// readerArgs are the args of the readers of the sheets discovered after the reader creation
+	readerArgs BatchReaderArgs
+	// includeSheets and excludeSheets are the title patterns of the discovered sheets
+	includeSheets *regexp.Regexp
+	excludeSheets *regexp.Regexp
+	// morePages is set when a page of BatchSize rows fetched by the last GetSheetRecords call was truncated,
+	// the empty pages being fetched on the next poll
+	morePages bool
+}
+
+func NewMultiReader(ctx context.Context, args MultiReaderArgs) (*MultiReader, error) {
+	sheetService, err := newService(ctx, args.ClientArgs)
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := getSpreadsheet(ctx, sheetService, args.SpreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MultiReader{
+		spreadsheetID:        args.SpreadsheetID,
+		sheetSvc:             sheetService,
+		backoff:              backoff{pollingPeriod: args.PollingPeriod},
+		dateTimeRenderOption: args.DateTimeRenderOption,
+		valueRenderOption:    args.ValueRenderOption,
+		readerArgs:           args.BatchReaderArgs,
+		includeSheets:        args.IncludeSheets,
+		excludeSheets:        args.ExcludeSheets,
+	}
+	if args.AllSheets {
+		if _, err = m.SetSheets(discoverSheets(spreadsheet, m.includeSheets, m.excludeSheets)); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+
+	if args.NamedRange != "" {
+		sheetProperties, err := findNamedRangeSheet(spreadsheet, args.SpreadsheetID, args.NamedRange)
+		if err != nil {
+			return nil, err
+		}
+		reader, err := newBatchReader(sheetService, spreadsheet, sheetProperties, args.BatchReaderArgs)
+		if err != nil {
+			return nil, err
+		}
+		m.readers = []*BatchReader{reader}
+		return m, nil
+	}
+
+	locators := args.Sheets
+	if len(locators) == 0 {
+		locators = []SheetLocator{{ID: args.SheetID, Name: args.SheetName}}
+	}
+	readers := make([]*BatchReader, 0, len(locators))
+	seen := make(map[int64]bool, len(locators))
+	for _, locator := range locators {
+		sheetProperties, err := findSheet(spreadsheet, args.SpreadsheetID, locator.ID, locator.Name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[sheetProperties.SheetId] {
+			return nil, fmt.Errorf("sheet(gid:%d) is listed more than once", sheetProperties.SheetId)
+		}
+		seen[sheetProperties.SheetId] = true
+
+		reader, err := newBatchReader(sheetService, spreadsheet, sheetProperties, args.BatchReaderArgs)
+		if err != nil {
+			return nil, err
+		}
+		readers = append(readers, reader)
+	}
+
+	m.readers = readers
+	return m, nil
+}
+
+// SheetIDs returns the gids of the sheets read, in the order of the sheets in the args
+func (m *MultiReader) SheetIDs() []int64 {
+	sheetIDs := make([]int64, 0, len(m.readers))
+	for _, reader := range m.readers {
+		sheetIDs = append(sheetIDs, reader.sheetID)
+	}
+	return sheetIDs
+}
+
+// SetRowStates sets the last known content of the rows of the sheets, by gid
+func (m *MultiReader) SetRowStates(rows map[int64]map[int64]state.Row) {
+	for _, reader := range m.readers {
+		reader.SetRowStates(rows[reader.sheetID])
+	}
+}
+
+// RowStates returns a copy of the last known content of the rows of the sheets, by gid,
+// as of the last GetSheetRecords call
+func (m *MultiReader) RowStates() map[int64]map[int64]state.Row {
+	rows := make(map[int64]map[int64]state.Row, len(m.readers))
+	for _, reader := range m.readers {
+		rows[reader.sheetID] = reader.RowStates()
+	}
+	return rows
+}
+
+// MorePages reports whether a sheet may have more rows to fetch right away, after the page of BatchSize rows
+// fetched by the last GetSheetRecords call
+func (m *MultiReader) MorePages() bool {
+	return m.morePages
+}
+
+// GetSheetRecords returns the records of the rows of all the sheets added after their row offsets, by gid,
+// the records of a sheet following the ones of the previous sheet.
+// The position of each record holds the row offsets of all the sheets as of the record.
+func (m *MultiReader) GetSheetRecords(ctx context.Context, offsets map[int64]int64) ([]opencdc.Record, error) {
+	m.morePages = false
+	if m.nextRun.After(time.Now()) || len(m.readers) == 0 {
+		return nil, nil
+	}
+
+	offsets = m.namedRangeOffsets(offsets)
+	starts := make([]int64, len(m.readers))
+	filterCounts := make([]int, len(m.readers))
+	dataFilters := make([]*sheets.DataFilter, 0)
+	for i, reader := range m.readers {
+		starts[i] = reader.startRow(offsets[reader.sheetID])
+		sheetFilters := reader.dataFilters(starts[i])
+		filterCounts[i] = len(sheetFilters)
+		dataFilters = append(dataFilters, sheetFilters...)
+	}
+	if len(dataFilters) == 0 {
+		// all the rows of the ranges are read
+		return nil, nil
+	}
+
+	res, err := m.sheetSvc.Spreadsheets.Values.BatchGetByDataFilter(m.spreadsheetID, &sheets.BatchGetValuesByDataFilterRequest{
+		DataFilters:          dataFilters,
+		DateTimeRenderOption: m.dateTimeRenderOption,
+		MajorDimension:       majorDimension,
+		ValueRenderOption:    m.valueRenderOption,
+	}).Context(ctx).Do()
+	if err != nil {
+		if err = m.checkRetryable(ctx, err); err != nil {
+			return nil, fmt.Errorf("error getting spreadsheet(%s) values, %w", m.spreadsheetID, err)
+		}
+		return nil, nil
+	}
+	// the value ranges are returned in the order of the data filters
+	if len(res.ValueRanges) != len(dataFilters) {
+		return nil, fmt.Errorf("error getting spreadsheet(%s) values, got %d value ranges for %d data filters",
+			m.spreadsheetID, len(res.ValueRanges), len(dataFilters))
+	}
+
+	sheetOffsets := maps.Clone(offsets)
+	if sheetOffsets == nil {
+		sheetOffsets = make(map[int64]int64, len(m.readers))
+	}
+	records := make([]opencdc.Record, 0)
+	states := make([]readerState, len(m.readers))
+	valueRanges := res.ValueRanges
+	for i, reader := range m.readers {
+		sheetRecords, next, err := reader.sheetRecords(ctx, valueRanges[:filterCounts[i]], starts[i], offsets[reader.sheetID])
+		if err != nil {
+			return nil, m.checkRetryable(ctx, err)
+		}
+		valueRanges = valueRanges[filterCounts[i]:]
+		// the sheet of a named range is located again when the range is moved to another sheet
+		sheetOffsets = m.namedRangeOffsets(sheetOffsets)
+
+		if err := setSheetOffsets(reader.sheetID, sheetRecords, sheetOffsets); err != nil {
+			return nil, err
+		}
+		records = append(records, sheetRecords...)
+		states[i] = next
+	}
+	// the readers move on once the records of all the sheets are built, so a failed sheet doesn't lose
+	// the changes detected in the sheets before it, which are detected again on the next poll
+	for i, reader := range m.readers {
+		reader.setState(states[i])
+		m.morePages = m.morePages || reader.morePages()
+	}
+	m.retryCount = 0
+	return records, nil
+}
+
+// namedRangeOffsets returns the row offsets by gid with the offset of the named range, if read, under the gid
+// of its current sheet, the offsets holding the gid of the sheet the range was in as of the last record read,
+// before it was moved to another sheet
+func (m *MultiReader) namedRangeOffsets(offsets map[int64]int64) map[int64]int64 {
+	if len(m.readers) != 1 || m.readers[0].namedRange == "" || len(offsets) != 1 {
+		return offsets
+	}
+	sheetID := m.readers[0].sheetID
+	if _, ok := offsets[sheetID]; ok {
+		return offsets
+	}
+	for _, offset := range offsets {
+		return map[int64]int64{sheetID: offset}
+	}
+	return offsets
+}
+
+// setSheetOffsets adds the row offsets of all the sheets as of each record of the sheet to the record positions,
+// moving the row offset of the sheet in the sheetOffsets
+func setSheetOffsets(sheetID int64, records []opencdc.Record, sheetOffsets map[int64]int64) error {
+	for i, record := range records {
+		pos, err := position.ParseRecordPosition(record.Position)
+		if err != nil {
+			return fmt.Errorf("failed to parse record position: %w", err)
+		}
+		sheetOffsets[sheetID] = max(sheetOffsets[sheetID], pos.RowOffset)
+		pos.SheetOffsets = maps.Clone(sheetOffsets)
+		records[i].Position = pos.RecordPosition()
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/position"
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/state"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// newMultiSheetServer returns a test server with the spreadsheet metadata of two sheets(tabs),
+// answering the BatchGetByDataFilter requests with the valueRanges and recording the requests
+func newMultiSheetServer(t *testing.T, valueRanges string, requests *[]sheets.BatchGetValuesByDataFilterRequest) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"properties":{"title":"Dummy"},"sheets":[
+			{"properties":{"sheetId":0,"title":"Orders","index":0}},
+			{"properties":{"sheetId":1234,"title":"Returns","index":1}}
+		]}`))
+	})
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet/values:batchGetByDataFilter", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var request sheets.BatchGetValuesByDataFilterRequest
+		assert.NoError(t, json.Unmarshal(body, &request))
+		*requests = append(*requests, request)
+		_, _ = w.Write([]byte(`{"spreadsheetId":"dummy_spreadsheet","valueRanges":` + valueRanges + `}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestNewMultiReader(t *testing.T) {
+	var requests []sheets.BatchGetValuesByDataFilterRequest
+	testServer := newMultiSheetServer(t, `[]`, &requests)
+	defer testServer.Close()
+	args := BatchReaderArgs{
+		ClientArgs: ClientArgs{
+			TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+			Endpoint:    testServer.URL,
+		},
+		SpreadsheetID: "dummy_spreadsheet",
+		SheetID:       -1,
+	}
+
+	tests := []struct {
+		name   string
+		sheets []SheetLocator
+		want   []int64
+		err    string
+	}{{
+		name: "sheet of the reader args",
+		want: []int64{0},
+	}, {
+		name:   "sheets by gid and title",
+		sheets: []SheetLocator{{ID: 1234}, {ID: -1, Name: "Orders"}},
+		want:   []int64{1234, 0},
+	}, {
+		name:   "sheet listed twice",
+		sheets: []SheetLocator{{ID: 1234}, {ID: -1, Name: "Returns"}},
+		err:    "sheet(gid:1234) is listed more than once",
+	}, {
+		name:   "unknown sheet",
+		sheets: []SheetLocator{{ID: -1, Name: "Missing"}},
+		err:    `sheet("Missing") not found in spreadsheet(dummy_spreadsheet)`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMultiReader(context.Background(), MultiReaderArgs{BatchReaderArgs: args, Sheets: tt.sheets})
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.SheetIDs())
+		})
+	}
+}
+
+func TestMultiReader_GetSheetRecords(t *testing.T) {
+	var requests []sheets.BatchGetValuesByDataFilterRequest
+	testServer := newMultiSheetServer(t, `[
+		{"valueRange":{"values":[["o1"],["o2"]]}},
+		{"valueRange":{"values":[["r1"]]}}
+	]`, &requests)
+	defer testServer.Close()
+
+	reader, err := NewMultiReader(context.Background(), MultiReaderArgs{
+		BatchReaderArgs: BatchReaderArgs{
+			ClientArgs: ClientArgs{
+				TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+				Endpoint:    testServer.URL,
+			},
+			SpreadsheetID:     "dummy_spreadsheet",
+			ValueRenderOption: "FORMATTED_VALUE",
+		},
+		Sheets: []SheetLocator{{ID: 0}, {ID: 1234}},
+	})
+	assert.NoError(t, err)
+
+	records, err := reader.GetSheetRecords(context.Background(), map[int64]int64{0: 3, 1234: 7})
+	assert.NoError(t, err)
+
+	// a single request with the data filters of both sheets
+	assert.Len(t, requests, 1)
+	assert.Equal(t, []*sheets.DataFilter{
+		{GridRange: &sheets.GridRange{SheetId: 0, StartRowIndex: 3}},
+		{GridRange: &sheets.GridRange{SheetId: 1234, StartRowIndex: 7}},
+	}, requests[0].DataFilters)
+
+	want := []struct {
+		collection string
+		position   position.SheetPosition
+		payload    string
+	}{{
+		collection: "Orders",
+		position: position.SheetPosition{RowOffset: 4, SpreadsheetID: "dummy_spreadsheet", SheetID: 0,
+			SheetOffsets: map[int64]int64{0: 4, 1234: 7}},
+		payload: `["o1"]`,
+	}, {
+		collection: "Orders",
+		position: position.SheetPosition{RowOffset: 5, SpreadsheetID: "dummy_spreadsheet", SheetID: 0,
+			SheetOffsets: map[int64]int64{0: 5, 1234: 7}},
+		payload: `["o2"]`,
+	}, {
+		collection: "Returns",
+		position: position.SheetPosition{RowOffset: 8, SpreadsheetID: "dummy_spreadsheet", SheetID: 1234,
+			SheetOffsets: map[int64]int64{0: 5, 1234: 8}},
+		payload: `["r1"]`,
+	}}
+	assert.Len(t, records, len(want))
+	for i, record := range records {
+		collection, err := record.Metadata.GetCollection()
+		assert.NoError(t, err)
+		assert.Equal(t, want[i].collection, collection)
+		pos, err := position.ParseRecordPosition(record.Position)
+		assert.NoError(t, err)
+		assert.Equal(t, want[i].position, pos)
+		assert.Equal(t, opencdc.RawData(want[i].payload), record.Payload.After)
+	}
+}
+
+func TestMultiReader_GetSheetRecords_BatchSize(t *testing.T) {
+	var requests []sheets.BatchGetValuesByDataFilterRequest
+	testServer := newMultiSheetServer(t, `[
+		{"valueRange":{"values":[["o1"],["o2"]]}},
+		{"valueRange":{"values":[["r1"]]}}
+	]`, &requests)
+	defer testServer.Close()
+
+	reader, err := NewMultiReader(context.Background(), MultiReaderArgs{
+		BatchReaderArgs: BatchReaderArgs{
+			ClientArgs: ClientArgs{
+				TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+				Endpoint:    testServer.URL,
+			},
+			SpreadsheetID: "dummy_spreadsheet",
+			BatchSize:     2,
+		},
+		Sheets: []SheetLocator{{ID: 0}, {ID: 1234}},
+	})
+	assert.NoError(t, err)
+
+	records, err := reader.GetSheetRecords(context.Background(), map[int64]int64{0: 3, 1234: 7})
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+	// the page of the first sheet is full
+	assert.True(t, reader.MorePages())
+
+	_, err = reader.GetSheetRecords(context.Background(), map[int64]int64{0: 5, 1234: 8})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+	assert.Equal(t, []*sheets.DataFilter{
+		{GridRange: &sheets.GridRange{SheetId: 0, StartRowIndex: 3, EndRowIndex: 5}},
+		{GridRange: &sheets.GridRange{SheetId: 1234, StartRowIndex: 7, EndRowIndex: 9}},
+	}, requests[0].DataFilters)
+	assert.Equal(t, []*sheets.DataFilter{
+		{GridRange: &sheets.GridRange{SheetId: 0, StartRowIndex: 5, EndRowIndex: 7}},
+		{GridRange: &sheets.GridRange{SheetId: 1234, StartRowIndex: 8, EndRowIndex: 10}},
+	}, requests[1].DataFilters)
+}
+
+func TestMultiReader_GetSheetRecords_RetryableErrorKeepsStates(t *testing.T) {
+	var cellData int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet/values:batchGetByDataFilter", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"valueRanges":[
+			{"valueRange":{"values":[["changed"]]}},
+			{"valueRange":{"values":[["1"]]}}
+		]}`))
+	})
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet:getByDataFilter", func(w http.ResponseWriter, _ *http.Request) {
+		cellData++
+		if cellData == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"sheets":[{"data":[{"rowData":[{"values":[{"effectiveValue":{"numberValue":1}}]}]}]}]}`))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	sheetSvc, err := sheets.NewService(
+		context.Background(),
+		option.WithEndpoint(testServer.URL),
+		option.WithHTTPClient(&http.Client{}))
+	assert.NoError(t, err)
+
+	orders := &BatchReader{spreadsheetID: "dummy_spreadsheet", sheetID: 0, sheetSvc: sheetSvc, detectUpdates: true}
+	orders.SetRowStates(map[int64]state.Row{1: state.NewRow([]byte(`["original"]`))})
+	returns := &BatchReader{spreadsheetID: "dummy_spreadsheet", sheetID: 1234, sheetSvc: sheetSvc, typedValues: true, location: time.UTC}
+	reader := &MultiReader{
+		spreadsheetID: "dummy_spreadsheet",
+		sheetSvc:      sheetSvc,
+		readers:       []*BatchReader{orders, returns},
+	}
+
+	// the cell data request of the second sheet is rate limited, the first sheet keeps its row states
+	records, err := reader.GetSheetRecords(context.Background(), map[int64]int64{0: 1, 1234: 0})
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+	assert.Equal(t, map[int64]state.Row{1: state.NewRow([]byte(`["original"]`))}, orders.RowStates())
+
+	// the update of the first sheet is detected again on the next poll
+	reader.nextRun = time.Time{}
+	records, err = reader.GetSheetRecords(context.Background(), map[int64]int64{0: 1, 1234: 0})
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, opencdc.OperationUpdate, records[0].Operation)
+	assert.Equal(t, opencdc.RawData(`["original"]`), records[0].Payload.Before)
+	assert.Equal(t, opencdc.RawData(`["changed"]`), records[0].Payload.After)
+	assert.Equal(t, opencdc.RawData(`[1]`), records[1].Payload.After)
+	assert.Equal(t, map[int64]state.Row{1: state.NewRow([]byte(`["changed"]`))}, orders.RowStates())
+}
+
+// newGridServer returns a test server with the spreadsheet metadata of a sheet of rowCount grid rows,
+// answering the BatchGetByDataFilter requests with the first rows of the sheet holding values, and recording the requests
+func newGridServer(t *testing.T, rowCount int64, rows *int64, requests *[]sheets.BatchGetValuesByDataFilterRequest) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"properties":{"title":"Dummy"},"sheets":[
+			{"properties":{"sheetId":0,"title":"Orders","index":0,"gridProperties":{"rowCount":%d}}}
+		]}`, rowCount)))
+	})
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet/values:batchGetByDataFilter", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var request sheets.BatchGetValuesByDataFilterRequest
+		assert.NoError(t, json.Unmarshal(body, &request))
+		*requests = append(*requests, request)
+
+		// the trailing empty rows aren't returned
+		gridRange := request.DataFilters[0].GridRange
+		values := make([][]interface{}, 0)
+		for row := gridRange.StartRowIndex; row < min(gridRange.EndRowIndex, *rows); row++ {
+			values = append(values, []interface{}{fmt.Sprintf("r%d", row+1)})
+		}
+		_ = json.NewEncoder(w).Encode(sheets.BatchGetValuesByDataFilterResponse{
+			SpreadsheetId: "dummy_spreadsheet",
+			ValueRanges:   []*sheets.MatchedValueRange{{ValueRange: &sheets.ValueRange{Values: values}}},
+		})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestMultiReader_GetSheetRecords_BatchSizeBlankGrid(t *testing.T) {
+	// the sheet holds 50 rows in a grid of 1000 rows
+	var (
+		requests []sheets.BatchGetValuesByDataFilterRequest
+		rows     int64 = 50
+	)
+	testServer := newGridServer(t, 1000, &rows, &requests)
+	defer testServer.Close()
+
+	reader, err := NewMultiReader(context.Background(), MultiReaderArgs{
+		BatchReaderArgs: BatchReaderArgs{
+			ClientArgs: ClientArgs{
+				TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+				Endpoint:    testServer.URL,
+			},
+			SpreadsheetID: "dummy_spreadsheet",
+			BatchSize:     10,
+		},
+	})
+	assert.NoError(t, err)
+
+	// poll runs a poll cycle like the iterator, fetching the next pages right away, returning the requests count
+	offsets := map[int64]int64{}
+	var records []opencdc.Record
+	poll := func() int {
+		sent := len(requests)
+		records = records[:0]
+		for {
+			pageRecords, err := reader.GetSheetRecords(context.Background(), offsets)
+			assert.NoError(t, err)
+			for _, record := range pageRecords {
+				pos, err := position.ParseRecordPosition(record.Position)
+				assert.NoError(t, err)
+				offsets = pos.SheetOffsets
+			}
+			records = append(records, pageRecords...)
+			if !reader.MorePages() {
+				return len(requests) - sent
+			}
+		}
+	}
+
+	// the five full pages, and the first empty page
+	assert.Equal(t, 6, poll())
+	assert.Len(t, records, 50)
+	// the empty pages up to the end of the grid are walked one per poll
+	for page := int64(6); page < 100; page++ {
+		assert.Equal(t, 1, poll())
+		assert.Equal(t, page*10, requests[len(requests)-1].DataFilters[0].GridRange.StartRowIndex)
+	}
+	// the empty tail is known, the polls only fetch the page after the offset
+	for range 3 {
+		assert.Equal(t, 1, poll())
+		assert.Equal(t, int64(50), requests[len(requests)-1].DataFilters[0].GridRange.StartRowIndex)
+	}
+
+	rows = 52
+	assert.Equal(t, 1, poll())
+	assert.Len(t, records, 2)
+	assert.Equal(t, 1, poll())
+	assert.Equal(t, int64(52), requests[len(requests)-1].DataFilters[0].GridRange.StartRowIndex)
+}
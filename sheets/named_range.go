@@ -0,0 +1,82 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// namedRangeValues splits the values of the named range, fetched as a whole, into the header row, if any,
+// and the rows from the 0-based start row in the range, as the value ranges fetched for the rows of a sheet.
+// The sheet indices of the first cell of the range are kept for the records metadata, and the sheet of the range
+// is located again when the range was moved to another sheet.
+func (b *BatchReader) namedRangeValues(
+	ctx context.Context,
+	valueRanges []*sheets.MatchedValueRange,
+	start int64,
+) ([]*sheets.MatchedValueRange, error) {
+	if len(valueRanges) != 1 || valueRanges[0].ValueRange == nil {
+		return nil, fmt.Errorf("got %d value ranges for the named range(%q)", len(valueRanges), b.namedRange)
+	}
+	valueRange := valueRanges[0].ValueRange
+
+	// the range of the value range is the A1 notation of the named range as of the fetch, e.g. Sheet1!B5:F20
+	cellRange, err := ParseA1Range(valueRange.Range)
+	if err != nil {
+		return nil, fmt.Errorf("error locating the named range(%q): %w", b.namedRange, err)
+	}
+	b.rangeStartRow = cellRange.StartRow
+	b.rangeStartColumn = cellRange.StartColumn
+	if cellRange.SheetName != "" && cellRange.SheetName != b.sheetTitle {
+		// the named range was moved to another sheet, or its sheet was renamed
+		if err := b.locateNamedRange(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	values := valueRange.Values
+	split := make([]*sheets.MatchedValueRange, 0, 2)
+	if b.headerRow > 0 {
+		var header [][]any
+		if b.headerRow <= int64(len(values)) {
+			header = values[b.headerRow-1 : b.headerRow]
+		}
+		split = append(split, &sheets.MatchedValueRange{ValueRange: &sheets.ValueRange{Values: header}})
+	}
+	var rows [][]any
+	if start < int64(len(values)) {
+		rows = values[start:]
+	}
+	return append(split, &sheets.MatchedValueRange{ValueRange: &sheets.ValueRange{Values: rows}}), nil
+}
+
+// locateNamedRange sets the sheet of the named range from the spreadsheet metadata
+func (b *BatchReader) locateNamedRange(ctx context.Context) error {
+	spreadsheet, err := getSpreadsheet(ctx, b.sheetSvc, b.spreadsheetID)
+	if err != nil {
+		return err
+	}
+	sheetProperties, err := findNamedRangeSheet(spreadsheet, b.spreadsheetID, b.namedRange)
+	if err != nil {
+		return err
+	}
+	b.sheetID = sheetProperties.SheetId
+	b.sheetTitle = sheetProperties.Title
+	b.rowCount = gridRowCount(sheetProperties)
+	return nil
+}
@@ -0,0 +1,172 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/position"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/sheets/v4"
+)
+
+// newNamedRangeServer returns a test server with the spreadsheet metadata of the OpenOrders named range, in the Orders
+// sheet and then in the Summary sheet once the range is moved to it, answering the BatchGetByDataFilter requests
+// with the ranges in turn, the last one being repeated, and recording the requests
+func newNamedRangeServer(t *testing.T, ranges []string, moved *bool, requests *[]sheets.BatchGetValuesByDataFilterRequest) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet", func(w http.ResponseWriter, _ *http.Request) {
+		sheetID := 1234
+		if *moved {
+			sheetID = 0
+		}
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"properties":{"title":"Dummy"},"sheets":[
+			{"properties":{"sheetId":0,"title":"Summary","index":0}},
+			{"properties":{"sheetId":1234,"title":"Orders","index":1}}
+		],"namedRanges":[{"name":"OpenOrders","range":{"sheetId":%d}}]}`, sheetID)))
+	})
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet/values:batchGetByDataFilter", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var request sheets.BatchGetValuesByDataFilterRequest
+		assert.NoError(t, json.Unmarshal(body, &request))
+		valueRange := ranges[min(len(*requests), len(ranges)-1)]
+		*requests = append(*requests, request)
+		_, _ = w.Write([]byte(`{"spreadsheetId":"dummy_spreadsheet","valueRanges":[{"valueRange":` + valueRange + `}]}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+// newNamedRangeReader returns the reader of the OpenOrders named range, with a header row
+func newNamedRangeReader(t *testing.T, testServer *httptest.Server) *MultiReader {
+	t.Helper()
+	reader, err := NewMultiReader(context.Background(), MultiReaderArgs{
+		BatchReaderArgs: BatchReaderArgs{
+			ClientArgs: ClientArgs{
+				TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+				Endpoint:    testServer.URL,
+			},
+			SpreadsheetID: "dummy_spreadsheet",
+			SheetID:       -1,
+			HeaderRow:     1,
+			NamedRange:    "OpenOrders",
+		},
+	})
+	assert.NoError(t, err)
+	return reader
+}
+
+func TestMultiReader_GetSheetRecords_NamedRange(t *testing.T) {
+	// the OpenOrders named range is in the Orders sheet, the owner moving it down between the two polls
+	ranges := []string{
+		`{"range":"Orders!B5:D8","values":[["id","item"],["1","pen"],["2","ink"]]}`,
+		`{"range":"Orders!B7:D10","values":[["id","item"],["1","pen"],["2","ink"],["3","pad"]]}`,
+	}
+	var (
+		requests []sheets.BatchGetValuesByDataFilterRequest
+		moved    bool
+	)
+	testServer := newNamedRangeServer(t, ranges, &moved, &requests)
+	defer testServer.Close()
+
+	reader := newNamedRangeReader(t, testServer)
+	assert.Equal(t, []int64{1234}, reader.SheetIDs())
+
+	records, err := reader.GetSheetRecords(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, []*sheets.DataFilter{{A1Range: "OpenOrders"}}, requests[0].DataFilters)
+	// the row offsets are relative to the range, the metadata holding the sheet row and cells
+	pos, err := position.ParseRecordPosition(records[1].Position)
+	assert.NoError(t, err)
+	assert.Equal(t, position.SheetPosition{RowOffset: 3, SpreadsheetID: "dummy_spreadsheet", SheetID: 1234,
+		SheetOffsets: map[int64]int64{1234: 3}}, pos)
+	assert.Equal(t, opencdc.StructuredData{"id": "2", "item": "ink"}, records[1].Payload.After)
+	assert.Equal(t, "7", records[1].Metadata[MetadataRowNumber])
+	assert.Equal(t, "Orders!B7:C7", records[1].Metadata[MetadataRange])
+
+	// the range moved down two rows, only its new row is read
+	records, err = reader.GetSheetRecords(context.Background(), pos.SheetOffsets)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	pos, err = position.ParseRecordPosition(records[0].Position)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), pos.RowOffset)
+	assert.Equal(t, opencdc.StructuredData{"id": "3", "item": "pad"}, records[0].Payload.After)
+	assert.Equal(t, "10", records[0].Metadata[MetadataRowNumber])
+}
+
+func TestMultiReader_GetSheetRecords_NamedRangeMovedSheet(t *testing.T) {
+	// the OpenOrders named range is moved from the Orders sheet to the Summary sheet between the two polls
+	ranges := []string{
+		`{"range":"Orders!B5:D7","values":[["id","item"],["1","pen"]]}`,
+		`{"range":"Summary!A2:C5","values":[["id","item"],["1","pen"],["2","ink"]]}`,
+	}
+	var (
+		requests []sheets.BatchGetValuesByDataFilterRequest
+		moved    bool
+	)
+	testServer := newNamedRangeServer(t, ranges, &moved, &requests)
+	defer testServer.Close()
+
+	reader := newNamedRangeReader(t, testServer)
+	records, err := reader.GetSheetRecords(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	pos, err := position.ParseRecordPosition(records[0].Position)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1234), pos.SheetID)
+
+	moved = true
+	records, err = reader.GetSheetRecords(context.Background(), pos.SheetOffsets)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, []int64{0}, reader.SheetIDs())
+	// the offset of the range follows it to its new sheet
+	pos, err = position.ParseRecordPosition(records[0].Position)
+	assert.NoError(t, err)
+	assert.Equal(t, position.SheetPosition{RowOffset: 3, SpreadsheetID: "dummy_spreadsheet", SheetID: 0,
+		SheetOffsets: map[int64]int64{0: 3}}, pos)
+	assert.Equal(t, opencdc.StructuredData{"id": "2", "item": "ink"}, records[0].Payload.After)
+	assert.Equal(t, "Summary", records[0].Metadata[opencdc.MetadataCollection])
+	assert.Equal(t, "0", records[0].Metadata[MetadataSheetID])
+	assert.Equal(t, "Summary!A4:B4", records[0].Metadata[MetadataRange])
+}
+
+func TestNewMultiReader_UnknownNamedRange(t *testing.T) {
+	testServer := newMetadataServer(t, 0, "Sheet1")
+	defer testServer.Close()
+
+	_, err := NewMultiReader(context.Background(), MultiReaderArgs{
+		BatchReaderArgs: BatchReaderArgs{
+			ClientArgs: ClientArgs{
+				TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+				Endpoint:    testServer.URL,
+			},
+			SpreadsheetID: "dummy_spreadsheet",
+			NamedRange:    "OpenOrders",
+		},
+	})
+	assert.EqualError(t, err, `named range("OpenOrders") not found in spreadsheet(dummy_spreadsheet)`)
+}
@@ -0,0 +1,137 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// a1CellRegexp matches a cell reference of a range in A1 notation, the column letters and/or the row number, e.g. B2, B or 2
+var a1CellRegexp = regexp.MustCompile(`^([A-Za-z]{0,3})([0-9]*)$`)
+
+// CellRange is the range of the cells read in a sheet, in GridRange indices: 0-based, the end index being exclusive,
+// and a zero end index meaning unbounded. The zero value is the whole sheet.
+type CellRange struct {
+	// SheetName is the sheet title in the A1 notation, empty if none
+	SheetName   string
+	StartRow    int64
+	EndRow      int64
+	StartColumn int64
+	EndColumn   int64
+}
+
+// ParseA1Range parses a range in A1 notation, e.g. B:F, A2:K or 'My Data'!B2:F100
+func ParseA1Range(a1 string) (CellRange, error) {
+	var cellRange CellRange
+	cells := strings.TrimSpace(a1)
+	if i := strings.LastIndex(cells, "!"); i >= 0 {
+		cellRange.SheetName = unquoteSheetTitle(cells[:i])
+		if cellRange.SheetName == "" {
+			return CellRange{}, fmt.Errorf("range %q has an empty sheet name", a1)
+		}
+		cells = cells[i+1:]
+	}
+
+	startCell, endCell, isRange := strings.Cut(cells, ":")
+	if !isRange {
+		// a single cell, or a single column or row
+		endCell = startCell
+	}
+	startColumn, startRow, err := parseA1Cell(startCell)
+	if err != nil {
+		return CellRange{}, fmt.Errorf("invalid range %q: %w", a1, err)
+	}
+	endColumn, endRow, err := parseA1Cell(endCell)
+	if err != nil {
+		return CellRange{}, fmt.Errorf("invalid range %q: %w", a1, err)
+	}
+
+	if startColumn > 0 {
+		cellRange.StartColumn = startColumn - 1
+	}
+	cellRange.EndColumn = endColumn
+	if startRow > 0 {
+		cellRange.StartRow = startRow - 1
+	}
+	cellRange.EndRow = endRow
+	if err := cellRange.Validate(); err != nil {
+		return CellRange{}, fmt.Errorf("invalid range %q: %w", a1, err)
+	}
+	return cellRange, nil
+}
+
+// parseA1Cell returns the 1-based column and row numbers of the cell reference, 0 for a missing column or row
+func parseA1Cell(cell string) (int64, int64, error) {
+	matches := a1CellRegexp.FindStringSubmatch(strings.TrimSpace(cell))
+	if matches == nil || (matches[1] == "" && matches[2] == "") {
+		return 0, 0, fmt.Errorf("%q isn't a cell reference", cell)
+	}
+
+	var column, row int64
+	if matches[1] != "" {
+		column = int64(columnIndex(strings.ToUpper(matches[1]))) + 1
+	}
+	if matches[2] != "" {
+		var err error
+		row, err = strconv.ParseInt(matches[2], 10, 64)
+		if err != nil || row == 0 {
+			return 0, 0, fmt.Errorf("%q has an invalid row number", cell)
+		}
+	}
+	return column, row, nil
+}
+
+// Validate checks the indices are non-negative, and the end indices, if set, are after the start indices
+func (r CellRange) Validate() error {
+	switch {
+	case r.StartRow < 0 || r.EndRow < 0 || r.StartColumn < 0 || r.EndColumn < 0:
+		return fmt.Errorf("the indices should be non-negative")
+	case r.EndRow > 0 && r.EndRow <= r.StartRow:
+		return fmt.Errorf("the end row should be after the start row")
+	case r.EndColumn > 0 && r.EndColumn <= r.StartColumn:
+		return fmt.Errorf("the end column should be after the start column")
+	default:
+		return nil
+	}
+}
+
+// exhausted returns whether the rows of the range end before the 0-based start row
+func (r CellRange) exhausted(start int64) bool {
+	return r.EndRow > 0 && start >= r.EndRow
+}
+
+// gridRange returns the grid range of the cells of the sheet in the range, from the 0-based start row
+func (r CellRange) gridRange(sheetID, start int64) *sheets.GridRange {
+	return &sheets.GridRange{
+		SheetId:          sheetID,
+		StartRowIndex:    start,
+		EndRowIndex:      r.EndRow,
+		StartColumnIndex: r.StartColumn,
+		EndColumnIndex:   r.EndColumn,
+	}
+}
+
+// unquoteSheetTitle returns the sheet title of the A1 notation, unquoting it if quoted
+func unquoteSheetTitle(title string) string {
+	if len(title) >= 2 && strings.HasPrefix(title, "'") && strings.HasSuffix(title, "'") {
+		return strings.ReplaceAll(title[1:len(title)-1], "''", "'")
+	}
+	return title
+}
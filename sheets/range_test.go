@@ -0,0 +1,115 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestParseA1Range(t *testing.T) {
+	tests := []struct {
+		a1   string
+		want CellRange
+		err  string
+	}{{
+		a1:   "B:F",
+		want: CellRange{StartColumn: 1, EndColumn: 6},
+	}, {
+		a1:   "Data!A2:K",
+		want: CellRange{SheetName: "Data", StartRow: 1, EndColumn: 11},
+	}, {
+		a1:   "'Bob''s data'!b2:f100",
+		want: CellRange{SheetName: "Bob's data", StartRow: 1, EndRow: 100, StartColumn: 1, EndColumn: 6},
+	}, {
+		a1:   "2:100",
+		want: CellRange{StartRow: 1, EndRow: 100},
+	}, {
+		a1:   "C",
+		want: CellRange{StartColumn: 2, EndColumn: 3},
+	}, {
+		a1:  "F:B",
+		err: `invalid range "F:B": the end column should be after the start column`,
+	}, {
+		a1:  "A10:B2",
+		err: `invalid range "A10:B2": the end row should be after the start row`,
+	}, {
+		a1:  "A0:B",
+		err: `invalid range "A0:B": "A0" has an invalid row number`,
+	}, {
+		a1:  "Data!",
+		err: `invalid range "Data!": "" isn't a cell reference`,
+	}, {
+		a1:  "!A:B",
+		err: `range "!A:B" has an empty sheet name`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.a1, func(t *testing.T) {
+			got, err := ParseA1Range(tt.a1)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBatchReader_dataFilters_Range(t *testing.T) {
+	br := &BatchReader{
+		sheetID:   1234,
+		headerRow: 1,
+		cellRange: CellRange{StartRow: 1, EndRow: 100, StartColumn: 1, EndColumn: 6},
+	}
+	assert.Equal(t, int64(1), br.startRow(0))
+	assert.Equal(t, []*sheets.DataFilter{
+		{GridRange: &sheets.GridRange{SheetId: 1234, StartRowIndex: 0, EndRowIndex: 1, StartColumnIndex: 1, EndColumnIndex: 6}},
+		{GridRange: &sheets.GridRange{SheetId: 1234, StartRowIndex: 10, EndRowIndex: 100, StartColumnIndex: 1, EndColumnIndex: 6}},
+	}, br.dataFilters(10))
+
+	// all the rows of the range are read, only the header row is fetched
+	assert.Equal(t, []*sheets.DataFilter{
+		{GridRange: &sheets.GridRange{SheetId: 1234, StartRowIndex: 0, EndRowIndex: 1, StartColumnIndex: 1, EndColumnIndex: 6}},
+	}, br.dataFilters(100))
+}
+
+func TestBatchReader_valueRangesToRecords_Range(t *testing.T) {
+	in := []*sheets.MatchedValueRange{
+		{ValueRange: &sheets.ValueRange{Values: [][]any{{"id", ""}}}},
+		{ValueRange: &sheets.ValueRange{Values: [][]any{{"1", "x", "extra"}}}},
+	}
+
+	br := &BatchReader{
+		sheetID:       1234,
+		sheetTitle:    "Data",
+		spreadsheetID: "dummy_spreadsheet",
+		headerRow:     1,
+		keyColumns:    []string{"C"},
+		cellRange:     CellRange{StartColumn: 1},
+	}
+	out, _, err := br.valueRangesToRecords(in, nil, 1)
+	assert.NoError(t, err)
+	assert.Len(t, out, 1)
+
+	// the columns are named after their column in the sheet, the range starting at column B
+	assert.Equal(t, opencdc.StructuredData{"id": "1", "C": "x", "D": "extra"}, out[0].Payload.After)
+	assert.Equal(t, opencdc.StructuredData{"C": "x"}, out[0].Key)
+	assert.Equal(t, opencdc.Position(`{"row_offset":2,"spreadsheet_id":"dummy_spreadsheet","sheet_id":1234}`), out[0].Position)
+	assert.Equal(t, "Data!B2:D2", out[0].Metadata[MetadataRange])
+}
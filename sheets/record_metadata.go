@@ -0,0 +1,80 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// Record metadata keys, see the source package for their documentation
+const (
+	MetadataSpreadsheetID    = "google-sheets.spreadsheet.id"
+	MetadataSpreadsheetTitle = "google-sheets.spreadsheet.title"
+	MetadataSheetID          = "google-sheets.sheet.id"
+	MetadataSheetTitle       = "google-sheets.sheet.title"
+	MetadataRowNumber        = "google-sheets.row"
+	MetadataRange            = "google-sheets.range"
+	MetadataDriveFileID      = "google-sheets.drive.file.id"
+	MetadataDriveFileName    = "google-sheets.drive.file.name"
+)
+
+var (
+	// unquotedSheetTitleRegexp matches the sheet titles which don't need to be quoted in A1 notation
+	unquotedSheetTitleRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+	// cellReferenceRegexp matches the sheet titles read as a cell reference when unquoted, e.g. Q1 or R1C1
+	cellReferenceRegexp = regexp.MustCompile(`^([a-zA-Z]{1,3}[0-9]+|[rR][0-9]+[cC][0-9]+)$`)
+)
+
+// rowMetadata returns the metadata of the records of the row, the 1-based row number
+func (b *BatchReader) rowMetadata(rowNumber int64) opencdc.Metadata {
+	metadata := opencdc.Metadata{
+		MetadataSpreadsheetID:    b.spreadsheetID,
+		MetadataSpreadsheetTitle: b.spreadsheetTitle,
+		MetadataSheetID:          strconv.FormatInt(b.sheetID, 10),
+		MetadataSheetTitle:       b.sheetTitle,
+		MetadataRowNumber:        strconv.FormatInt(b.rangeStartRow+rowNumber, 10),
+	}
+	if b.driveFile != nil {
+		metadata[MetadataDriveFileID] = b.driveFile.ID
+		metadata[MetadataDriveFileName] = b.driveFile.Name
+	}
+	metadata.SetCollection(b.sheetTitle)
+	metadata.SetCreatedAt(time.Now())
+	return metadata
+}
+
+// rowRange returns the A1 notation of the row cells, from the first column of the range,
+// e.g. Sheet1!A5:D5 for the row 5 with 4 cells
+func (b *BatchReader) rowRange(rowNumber int64, cells int) string {
+	first := int(b.rangeStartColumn + b.cellRange.StartColumn)
+	rowNumber += b.rangeStartRow
+	return fmt.Sprintf("%s!%s%d:%s%d", quoteSheetTitle(b.sheetTitle),
+		columnName(first), rowNumber, columnName(first+max(cells, 1)-1), rowNumber)
+}
+
+// quoteSheetTitle returns the sheet title as used in A1 notation, quoted if it has any special character
+// or looks like a cell reference
+func quoteSheetTitle(title string) string {
+	if unquotedSheetTitleRegexp.MatchString(title) && !cellReferenceRegexp.MatchString(title) {
+		return title
+	}
+	return "'" + strings.ReplaceAll(title, "'", "''") + "'"
+}
@@ -0,0 +1,74 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchReader_rowMetadata(t *testing.T) {
+	br := &BatchReader{
+		spreadsheetID:    "dummy_spreadsheet",
+		spreadsheetTitle: "Dummy",
+		sheetID:          1234,
+		sheetTitle:       "Form Responses 1",
+	}
+
+	metadata := br.rowMetadata(5)
+	_, err := metadata.GetCreatedAt()
+	assert.NoError(t, err)
+	delete(metadata, opencdc.MetadataCreatedAt)
+	assert.Equal(t, opencdc.Metadata{
+		MetadataSpreadsheetID:      "dummy_spreadsheet",
+		MetadataSpreadsheetTitle:   "Dummy",
+		MetadataSheetID:            "1234",
+		MetadataSheetTitle:         "Form Responses 1",
+		MetadataRowNumber:          "5",
+		opencdc.MetadataCollection: "Form Responses 1",
+	}, metadata)
+
+	assert.Equal(t, "'Form Responses 1'!A5:D5", br.rowRange(5, 4))
+}
+
+func TestBatchReader_rowMetadata_DriveFile(t *testing.T) {
+	br := &BatchReader{
+		spreadsheetID:    "file1",
+		spreadsheetTitle: "2024-01 Expenses",
+		sheetID:          0,
+		sheetTitle:       "Sheet1",
+		driveFile:        &DriveFile{ID: "file1", Name: "2024-01 Expenses"},
+	}
+
+	metadata := br.rowMetadata(2)
+	assert.Equal(t, "file1", metadata[MetadataDriveFileID])
+	assert.Equal(t, "2024-01 Expenses", metadata[MetadataDriveFileName])
+}
+
+func TestQuoteSheetTitle(t *testing.T) {
+	tests := map[string]string{
+		"Sheet1":      "Sheet1",
+		"my_sheet":    "my_sheet",
+		"Sheet 1":     "'Sheet 1'",
+		"Bob's sheet": "'Bob''s sheet'",
+		"Q1":          "'Q1'",
+		"R1C1":        "'R1C1'",
+	}
+	for title, want := range tests {
+		assert.Equal(t, want, quoteSheetTitle(title))
+	}
+}
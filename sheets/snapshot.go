@@ -0,0 +1,179 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"golang.org/x/time/rate"
+	"google.golang.org/api/sheets/v4"
+)
+
+// SnapshotArgs are the options of the parallel snapshot of the sheets, disabled when Concurrency is 0
+type SnapshotArgs struct {
+	// Concurrency is the maximum number of pages of BatchSize rows fetched concurrently, or waiting to be emitted
+	Concurrency int
+	// RequestsPerMinute is the maximum rate of the page requests
+	RequestsPerMinute int
+}
+
+// snapshotPage is the result of the requests of a page of the snapshot, the values of the rows
+// and, with typedValues, the cell data of the rows
+type snapshotPage struct {
+	valueRanges []*sheets.MatchedValueRange
+	rowData     []*sheets.RowData
+	err         error
+}
+
+// Snapshot reads the rows of the sheets after their row offsets, by gid, up to the last row of the sheet grids,
+// fetching up to Concurrency pages of BatchSize rows concurrently, within RequestsPerMinute. The records of each page
+// are passed to emit in row order, sheet after sheet, so the positions of the records are monotonic.
+// The snapshot stops at the first page which can't be fetched, the next rows being read by GetSheetRecords.
+func (m *MultiReader) Snapshot(
+	ctx context.Context,
+	offsets map[int64]int64,
+	args SnapshotArgs,
+	emit func([]opencdc.Record) error,
+) error {
+	if args.Concurrency <= 0 || args.RequestsPerMinute <= 0 {
+		return nil
+	}
+	sheetOffsets := maps.Clone(offsets)
+	if sheetOffsets == nil {
+		sheetOffsets = make(map[int64]int64, len(m.readers))
+	}
+	limiter := rate.NewLimiter(rate.Every(time.Minute/time.Duration(args.RequestsPerMinute)), 1)
+	for _, reader := range m.readers {
+		complete, err := reader.snapshot(ctx, sheetOffsets, args.Concurrency, limiter, emit)
+		if err != nil || !complete {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshot reads the pages of the sheet from its row offset up to the last row of the grid, reporting whether
+// all the pages were read. The pages are fetched by concurrent requests, each page holding a slot until its records
+// are emitted, so at most concurrency pages are fetched ahead of the emitted rows.
+func (b *BatchReader) snapshot(
+	ctx context.Context,
+	sheetOffsets map[int64]int64,
+	concurrency int,
+	limiter *rate.Limiter,
+	emit func([]opencdc.Record) error,
+) (bool, error) {
+	start := b.startRow(sheetOffsets[b.sheetID])
+	end := b.rowCount
+	if b.cellRange.EndRow > 0 {
+		end = min(end, b.cellRange.EndRow)
+	}
+	if b.batchSize == 0 || start >= end {
+		return true, nil
+	}
+	pages := int((end - start + b.batchSize - 1) / b.batchSize)
+
+	// the requests are canceled, and awaited, on return
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan snapshotPage, pages)
+	for i := range results {
+		results[i] = make(chan snapshotPage, 1)
+	}
+	slots := make(chan struct{}, concurrency)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range pages {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			if err := limiter.Wait(ctx); err != nil {
+				return
+			}
+			wg.Add(1)
+			go func(pageStart int64) {
+				defer wg.Done()
+				results[i] <- b.fetchPage(ctx, limiter, pageStart)
+			}(start + int64(i)*b.batchSize)
+		}
+	}()
+
+	for i := range pages {
+		var page snapshotPage
+		select {
+		case page = <-results[i]:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+		if page.err != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			sdk.Logger(ctx).Warn().Err(page.err).Msg("unable to fetch the snapshot page, reading the next rows by polling")
+			return false, nil
+		}
+		records, next, err := b.pageRecords(page.valueRanges, page.rowData, start+int64(i)*b.batchSize, sheetOffsets[b.sheetID])
+		if err != nil {
+			return false, err
+		}
+		b.setState(next)
+		if err := setSheetOffsets(b.sheetID, records, sheetOffsets); err != nil {
+			return false, err
+		}
+		if len(records) > 0 {
+			if err := emit(records); err != nil {
+				return false, err
+			}
+		}
+		if next.heldRow > 0 {
+			// the rows from the held row are read by polling, once its key is filled
+			return false, nil
+		}
+		// the page is emitted, its slot is free for the next page
+		<-slots
+	}
+	return true, nil
+}
+
+// fetchPage fetches the page of BatchSize rows from the start row, along with the header row, and the cell data
+// of the rows with typedValues, the cell data request being rate limited like the page requests
+func (b *BatchReader) fetchPage(ctx context.Context, limiter *rate.Limiter, start int64) snapshotPage {
+	res, err := b.sheetSvc.Spreadsheets.Values.BatchGetByDataFilter(b.spreadsheetID, b.getDataFilter(start)).Context(ctx).Do()
+	if err != nil {
+		return snapshotPage{err: fmt.Errorf("error getting sheet(gid:%v) values, %w", b.sheetID, err)}
+	}
+	page := snapshotPage{valueRanges: res.ValueRanges}
+	if !b.needsRowData(page.valueRanges, start) {
+		return page
+	}
+	if err := limiter.Wait(ctx); err != nil {
+		return snapshotPage{err: err}
+	}
+	if page.rowData, err = b.getRowData(ctx, start); err != nil {
+		return snapshotPage{err: err}
+	}
+	return page
+}
@@ -0,0 +1,246 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/position"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/sheets/v4"
+)
+
+// newSnapshotServer returns a test server with the spreadsheet metadata of a sheet of 10 rows, answering the page
+// requests with a row per page row, the first pages being answered last, and failing the page starting at failRow.
+// The cell data requests are answered with the row numbers as numbers, their grid ranges being recorded,
+// failing the cell data of the page starting at failCellDataRow.
+func newSnapshotServer(t *testing.T, failRow, failCellDataRow int64, gridRanges *[]*sheets.GridRange) *httptest.Server {
+	t.Helper()
+	var gridRangesMu sync.Mutex
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"properties":{"title":"Dummy"},"sheets":[
+			{"properties":{"sheetId":0,"title":"Orders","index":0,"gridProperties":{"rowCount":10}}}
+		]}`))
+	})
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet/values:batchGetByDataFilter", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var request sheets.BatchGetValuesByDataFilterRequest
+		assert.NoError(t, json.Unmarshal(body, &request))
+		rows := request.DataFilters[0].GridRange
+		if rows.StartRowIndex == failRow {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		time.Sleep(time.Duration(10-rows.StartRowIndex) * 5 * time.Millisecond)
+
+		values := make([][]interface{}, 0)
+		for row := rows.StartRowIndex; row < min(rows.EndRowIndex, 10); row++ {
+			values = append(values, []interface{}{fmt.Sprintf("r%d", row+1)})
+		}
+		_ = json.NewEncoder(w).Encode(sheets.BatchGetValuesByDataFilterResponse{
+			SpreadsheetId: "dummy_spreadsheet",
+			ValueRanges:   []*sheets.MatchedValueRange{{ValueRange: &sheets.ValueRange{Values: values}}},
+		})
+	})
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet:getByDataFilter", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var request sheets.GetSpreadsheetByDataFilterRequest
+		assert.NoError(t, json.Unmarshal(body, &request))
+		rows := request.DataFilters[0].GridRange
+		gridRangesMu.Lock()
+		*gridRanges = append(*gridRanges, rows)
+		gridRangesMu.Unlock()
+		if rows.StartRowIndex == failCellDataRow {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		rowData := make([]*sheets.RowData, 0)
+		for row := rows.StartRowIndex; row < min(rows.EndRowIndex, 10); row++ {
+			value := float64(row + 1)
+			rowData = append(rowData, &sheets.RowData{Values: []*sheets.CellData{{EffectiveValue: &sheets.ExtendedValue{NumberValue: &value}}}})
+		}
+		_ = json.NewEncoder(w).Encode(sheets.Spreadsheet{
+			Sheets: []*sheets.Sheet{{Data: []*sheets.GridData{{RowData: rowData}}}},
+		})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestMultiReader_Snapshot(t *testing.T) {
+	tests := []struct {
+		name    string
+		failRow int64
+		want    []int64
+	}{{
+		name:    "all the pages",
+		failRow: -1,
+		want:    []int64{4, 5, 6, 7, 8, 9, 10},
+	}, {
+		name:    "failed page",
+		failRow: 7,
+		want:    []int64{4, 5, 6, 7},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := newSnapshotServer(t, tt.failRow, -1, nil)
+			defer testServer.Close()
+
+			reader, err := NewMultiReader(context.Background(), MultiReaderArgs{
+				BatchReaderArgs: BatchReaderArgs{
+					ClientArgs: ClientArgs{
+						TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+						Endpoint:    testServer.URL,
+					},
+					SpreadsheetID: "dummy_spreadsheet",
+					BatchSize:     2,
+				},
+			})
+			assert.NoError(t, err)
+
+			var offsets []int64
+			err = reader.Snapshot(context.Background(), map[int64]int64{0: 3}, SnapshotArgs{
+				Concurrency:       3,
+				RequestsPerMinute: 6000,
+			}, func(records []opencdc.Record) error {
+				for _, record := range records {
+					pos, err := position.ParseRecordPosition(record.Position)
+					assert.NoError(t, err)
+					assert.Equal(t, map[int64]int64{0: pos.RowOffset}, pos.SheetOffsets)
+					assert.Equal(t, opencdc.RawData(fmt.Sprintf(`["r%d"]`, pos.RowOffset)), record.Payload.After)
+					offsets = append(offsets, pos.RowOffset)
+				}
+				return nil
+			})
+			assert.NoError(t, err)
+			// the records are emitted in row order
+			assert.Equal(t, tt.want, offsets)
+		})
+	}
+}
+
+func TestMultiReader_Snapshot_TypedValues(t *testing.T) {
+	var gridRanges []*sheets.GridRange
+	testServer := newSnapshotServer(t, -1, -1, &gridRanges)
+	defer testServer.Close()
+
+	reader, err := NewMultiReader(context.Background(), MultiReaderArgs{
+		BatchReaderArgs: BatchReaderArgs{
+			ClientArgs: ClientArgs{
+				TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+				Endpoint:    testServer.URL,
+			},
+			SpreadsheetID: "dummy_spreadsheet",
+			TypedValues:   true,
+			BatchSize:     4,
+		},
+	})
+	assert.NoError(t, err)
+
+	var payloads []opencdc.Data
+	err = reader.Snapshot(context.Background(), map[int64]int64{0: 2}, SnapshotArgs{
+		Concurrency:       2,
+		RequestsPerMinute: 6000,
+	}, func(records []opencdc.Record) error {
+		for _, record := range records {
+			payloads = append(payloads, record.Payload.After)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []opencdc.Data{
+		opencdc.RawData(`[3]`), opencdc.RawData(`[4]`), opencdc.RawData(`[5]`), opencdc.RawData(`[6]`),
+		opencdc.RawData(`[7]`), opencdc.RawData(`[8]`), opencdc.RawData(`[9]`), opencdc.RawData(`[10]`),
+	}, payloads)
+	// the cell data is fetched for the rows of each page only, by the concurrent page requests
+	assert.ElementsMatch(t, []*sheets.GridRange{{StartRowIndex: 2, EndRowIndex: 6}, {StartRowIndex: 6, EndRowIndex: 10}}, gridRanges)
+}
+
+func TestMultiReader_Snapshot_TypedValuesFailedCellData(t *testing.T) {
+	var gridRanges []*sheets.GridRange
+	testServer := newSnapshotServer(t, -1, 6, &gridRanges)
+	defer testServer.Close()
+
+	reader, err := NewMultiReader(context.Background(), MultiReaderArgs{
+		BatchReaderArgs: BatchReaderArgs{
+			ClientArgs: ClientArgs{
+				TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+				Endpoint:    testServer.URL,
+			},
+			SpreadsheetID: "dummy_spreadsheet",
+			TypedValues:   true,
+			BatchSize:     2,
+		},
+	})
+	assert.NoError(t, err)
+
+	var offsets []int64
+	err = reader.Snapshot(context.Background(), map[int64]int64{0: 2}, SnapshotArgs{
+		Concurrency:       2,
+		RequestsPerMinute: 6000,
+	}, func(records []opencdc.Record) error {
+		for _, record := range records {
+			pos, err := position.ParseRecordPosition(record.Position)
+			assert.NoError(t, err)
+			offsets = append(offsets, pos.RowOffset)
+		}
+		return nil
+	})
+	// the page whose cell data can't be fetched is read by polling, like a page which can't be fetched
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 4, 5, 6}, offsets)
+}
+
+func TestMultiReader_Snapshot_TypedValuesRateLimit(t *testing.T) {
+	var gridRanges []*sheets.GridRange
+	testServer := newSnapshotServer(t, -1, -1, &gridRanges)
+	defer testServer.Close()
+
+	reader, err := NewMultiReader(context.Background(), MultiReaderArgs{
+		BatchReaderArgs: BatchReaderArgs{
+			ClientArgs: ClientArgs{
+				TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+				Endpoint:    testServer.URL,
+			},
+			SpreadsheetID: "dummy_spreadsheet",
+			TypedValues:   true,
+			BatchSize:     4,
+		},
+	})
+	assert.NoError(t, err)
+
+	started := time.Now()
+	err = reader.Snapshot(context.Background(), map[int64]int64{0: 2}, SnapshotArgs{
+		Concurrency:       2,
+		RequestsPerMinute: 600,
+	}, func([]opencdc.Record) error { return nil })
+	assert.NoError(t, err)
+	assert.Len(t, gridRanges, 2)
+	// the 2 page requests and the 2 cell data requests are spaced by 100ms
+	assert.GreaterOrEqual(t, time.Since(started), 300*time.Millisecond)
+}
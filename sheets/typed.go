@@ -0,0 +1,159 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+)
+
+// cellFormatFields is the partial response field mask used to fetch only the effective value and number format type of the cells
+const cellFormatFields = "sheets.data(startRow,rowData.values(effectiveValue,effectiveFormat.numberFormat.type))"
+
+// maxSafeInteger is the largest integer a float64 holds exactly, larger numbers are kept as float64
+const maxSafeInteger = 1 << 53
+
+// serialEpoch is the day 0 of the Google Sheets date serial numbers
+var serialEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// getRowData returns the effective value and format of the cells in the rows of the range after the offset,
+// aligned with the rows of the values fetched from the same offset, within the same page of batchSize rows
+func (b *BatchReader) getRowData(ctx context.Context, offset int64) ([]*sheets.RowData, error) {
+	dataFilter := &sheets.DataFilter{GridRange: b.pageGridRange(offset)}
+	if b.namedRange != "" {
+		dataFilter = &sheets.DataFilter{A1Range: b.namedRange}
+	}
+	req := &sheets.GetSpreadsheetByDataFilterRequest{
+		DataFilters:     []*sheets.DataFilter{dataFilter},
+		IncludeGridData: true,
+	}
+	spreadsheet, err := b.sheetSvc.Spreadsheets.GetByDataFilter(b.spreadsheetID, req).
+		Fields(googleapi.Field(cellFormatFields)).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("error getting sheet(gid:%v) cell formats, %w", b.sheetID, err)
+	}
+
+	rowData := make([]*sheets.RowData, 0)
+	for _, sheet := range spreadsheet.Sheets {
+		for _, data := range sheet.Data {
+			rowData = append(rowData, data.RowData...)
+		}
+	}
+	if b.namedRange != "" {
+		// the named range is fetched as a whole, keep the rows from the offset in the range
+		if offset >= int64(len(rowData)) {
+			return nil, nil
+		}
+		rowData = rowData[offset:]
+	}
+	return rowData, nil
+}
+
+// typeValues replaces the rendered values of the value range with the typed values of the cells, in place
+func (b *BatchReader) typeValues(valueRange *sheets.ValueRange, rowData []*sheets.RowData) {
+	if valueRange == nil {
+		return
+	}
+	for i, row := range valueRange.Values {
+		// rows added between the two requests don't have cell data yet, keep their rendered values
+		if i >= len(rowData) || rowData[i] == nil {
+			break
+		}
+		for j := range row {
+			if j >= len(rowData[i].Values) {
+				break
+			}
+			row[j] = b.typedValue(row[j], rowData[i].Values[j])
+		}
+	}
+}
+
+// typedValue returns the typed value of the cell: booleans as bool, numbers as int64 when integral or float64 otherwise,
+// and date/time serials as time.Time when the dateTimeRenderOption is SERIAL_NUMBER.
+// The rendered value is kept for strings, errors and empty cells.
+func (b *BatchReader) typedValue(rendered any, cell *sheets.CellData) any {
+	if cell == nil || cell.EffectiveValue == nil {
+		return rendered
+	}
+	value := cell.EffectiveValue
+
+	switch {
+	case value.BoolValue != nil:
+		return *value.BoolValue
+	case value.NumberValue != nil:
+		number := *value.NumberValue
+		if isDateTimeFormat(cell) {
+			if b.dateTimeRenderOption != "SERIAL_NUMBER" {
+				return rendered
+			}
+			return serialToTime(number, b.location)
+		}
+		if number == math.Trunc(number) && math.Abs(number) <= maxSafeInteger {
+			return int64(number)
+		}
+		return number
+	default:
+		return rendered
+	}
+}
+
+// cellData returns the cell data of the column of the row, nil if the column isn't set or the cell data isn't fetched,
+// e.g. for the rows added between the values and the cell data requests
+func cellData(rowData []*sheets.RowData, row, column int) *sheets.CellData {
+	if column < 0 || row >= len(rowData) || rowData[row] == nil || column >= len(rowData[row].Values) {
+		return nil
+	}
+	return rowData[row].Values[column]
+}
+
+// isDateTimeFormat returns whether the cell's effective number format is a date and/or time
+func isDateTimeFormat(cell *sheets.CellData) bool {
+	if cell.EffectiveFormat == nil || cell.EffectiveFormat.NumberFormat == nil {
+		return false
+	}
+	switch cell.EffectiveFormat.NumberFormat.Type {
+	case "DATE", "TIME", "DATE_TIME":
+		return true
+	default:
+		return false
+	}
+}
+
+// isDateFormat returns whether the cell's effective number format is a date, with or without time
+func isDateFormat(cell *sheets.CellData) bool {
+	if cell == nil || cell.EffectiveFormat == nil || cell.EffectiveFormat.NumberFormat == nil {
+		return false
+	}
+	switch cell.EffectiveFormat.NumberFormat.Type {
+	case "DATE", "DATE_TIME":
+		return true
+	default:
+		return false
+	}
+}
+
+// serialToTime converts a date serial number, the days since 1899-12-30 with the time as the fraction of the day,
+// to time.Time, rounded to the millisecond. The serial is a wall clock time in the location.
+func serialToTime(serial float64, location *time.Location) time.Time {
+	millis := math.Round(serial * float64(24*time.Hour/time.Millisecond))
+	wallClock := serialEpoch.Add(time.Duration(millis) * time.Millisecond)
+	return time.Date(wallClock.Year(), wallClock.Month(), wallClock.Day(),
+		wallClock.Hour(), wallClock.Minute(), wallClock.Second(), wallClock.Nanosecond(), location)
+}
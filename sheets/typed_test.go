@@ -0,0 +1,252 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestBatchReader_typedValue(t *testing.T) {
+	number := func(v float64) *sheets.ExtendedValue { return &sheets.ExtendedValue{NumberValue: &v} }
+	boolean := func(v bool) *sheets.ExtendedValue { return &sheets.ExtendedValue{BoolValue: &v} }
+	format := func(typ string) *sheets.CellFormat {
+		return &sheets.CellFormat{NumberFormat: &sheets.NumberFormat{Type: typ}}
+	}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		dateTimeOption string
+		location       *time.Location
+		rendered       any
+		cell           *sheets.CellData
+		want           any
+	}{{
+		name:     "integer",
+		rendered: "1,234",
+		cell:     &sheets.CellData{EffectiveValue: number(1234), EffectiveFormat: format("NUMBER")},
+		want:     int64(1234),
+	}, {
+		name:     "float",
+		rendered: "12.5%",
+		cell:     &sheets.CellData{EffectiveValue: number(0.125), EffectiveFormat: format("PERCENT")},
+		want:     0.125,
+	}, {
+		name:     "boolean",
+		rendered: "TRUE",
+		cell:     &sheets.CellData{EffectiveValue: boolean(true)},
+		want:     true,
+	}, {
+		name:     "string",
+		rendered: "00123",
+		cell:     &sheets.CellData{EffectiveValue: &sheets.ExtendedValue{StringValue: new(string)}, EffectiveFormat: format("TEXT")},
+		want:     "00123",
+	}, {
+		name:           "date serial",
+		dateTimeOption: "SERIAL_NUMBER",
+		rendered:       45292.5,
+		cell:           &sheets.CellData{EffectiveValue: number(45292.5), EffectiveFormat: format("DATE_TIME")},
+		want:           time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC),
+	}, {
+		name:           "date serial in the spreadsheet time zone",
+		dateTimeOption: "SERIAL_NUMBER",
+		location:       newYork,
+		rendered:       45292.5,
+		cell:           &sheets.CellData{EffectiveValue: number(45292.5), EffectiveFormat: format("DATE_TIME")},
+		want:           time.Date(2024, time.January, 1, 12, 0, 0, 0, newYork),
+	}, {
+		name:           "formatted date",
+		dateTimeOption: "FORMATTED_STRING",
+		rendered:       "1/1/2024",
+		cell:           &sheets.CellData{EffectiveValue: number(45292), EffectiveFormat: format("DATE")},
+		want:           "1/1/2024",
+	}, {
+		name:     "empty cell",
+		rendered: "",
+		cell:     &sheets.CellData{},
+		want:     "",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			br := &BatchReader{dateTimeRenderOption: tt.dateTimeOption, location: time.UTC}
+			if tt.location != nil {
+				br.location = tt.location
+			}
+			assert.Equal(t, tt.want, br.typedValue(tt.rendered, tt.cell))
+		})
+	}
+}
+
+func TestBatchReader_GetSheetRecords_TypedValues(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet/values:batchGetByDataFilter", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"valueRanges":[{"valueRange":{"values":[["1","2.5","TRUE","text"],[],["3"]]}}]}`))
+	})
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet:getByDataFilter", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, cellFormatFields, r.URL.Query().Get("fields"))
+		_, _ = w.Write([]byte(`{"sheets":[{"data":[{"rowData":[
+			{"values":[{"effectiveValue":{"numberValue":1}},{"effectiveValue":{"numberValue":2.5}},{"effectiveValue":{"boolValue":true}},{"effectiveValue":{"stringValue":"text"}}]},
+			{}
+		]}]}]}`))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	sheetSvc, err := sheets.NewService(
+		context.Background(),
+		option.WithEndpoint(testServer.URL),
+		option.WithHTTPClient(&http.Client{}))
+	assert.NoError(t, err)
+
+	reader := &MultiReader{
+		spreadsheetID: "dummy_spreadsheet",
+		sheetSvc:      sheetSvc,
+		readers: []*BatchReader{{
+			spreadsheetID: "dummy_spreadsheet",
+			sheetID:       1234,
+			sheetSvc:      sheetSvc,
+			typedValues:   true,
+		}},
+	}
+	recs, err := reader.GetSheetRecords(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, recs, 2)
+	assert.Equal(t, opencdc.RawData(`[1,2.5,true,"text"]`), recs[0].Payload.After)
+	// the row added after the cell data was fetched keeps its rendered values
+	assert.Equal(t, opencdc.RawData(`["3"]`), recs[1].Payload.After)
+}
+
+func TestMultiReader_GetSheetRecords_TypedValuesBatchSize(t *testing.T) {
+	var gridRanges []*sheets.GridRange
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"properties":{"title":"Dummy"},"sheets":[
+			{"properties":{"sheetId":0,"title":"Orders","index":0,"gridProperties":{"rowCount":1000}}}
+		]}`))
+	})
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet/values:batchGetByDataFilter", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"valueRanges":[{"valueRange":{"values":[["1"],["2"]]}}]}`))
+	})
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet:getByDataFilter", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var request sheets.GetSpreadsheetByDataFilterRequest
+		assert.NoError(t, json.Unmarshal(body, &request))
+		gridRanges = append(gridRanges, request.DataFilters[0].GridRange)
+		_, _ = w.Write([]byte(`{"sheets":[{"data":[{"rowData":[
+			{"values":[{"effectiveValue":{"numberValue":1}}]},
+			{"values":[{"effectiveValue":{"numberValue":2}}]}
+		]}]}]}`))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	reader, err := NewMultiReader(context.Background(), MultiReaderArgs{
+		BatchReaderArgs: BatchReaderArgs{
+			ClientArgs: ClientArgs{
+				TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+				Endpoint:    testServer.URL,
+			},
+			SpreadsheetID: "dummy_spreadsheet",
+			TypedValues:   true,
+			BatchSize:     2,
+		},
+	})
+	assert.NoError(t, err)
+
+	recs, err := reader.GetSheetRecords(context.Background(), map[int64]int64{0: 10})
+	assert.NoError(t, err)
+	assert.Len(t, recs, 2)
+	assert.Equal(t, opencdc.RawData(`[1]`), recs[0].Payload.After)
+	// the cell data is fetched for the rows of the page only
+	assert.Equal(t, []*sheets.GridRange{{StartRowIndex: 10, EndRowIndex: 12}}, gridRanges)
+}
+
+func TestMultiReader_GetSheetRecords_TypedValuesBatchSize429(t *testing.T) {
+	var (
+		valueRanges []*sheets.GridRange
+		cellData    int
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"properties":{"title":"Dummy"},"sheets":[
+			{"properties":{"sheetId":0,"title":"Orders","index":0,"gridProperties":{"rowCount":1000}}}
+		]}`))
+	})
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet/values:batchGetByDataFilter", func(w http.ResponseWriter, r *http.Request) {
+		var request sheets.BatchGetValuesByDataFilterRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		valueRanges = append(valueRanges, request.DataFilters[0].GridRange)
+		_, _ = w.Write([]byte(`{"valueRanges":[{"valueRange":{"values":[["11"],["12"]]}}]}`))
+	})
+	mux.HandleFunc("/v4/spreadsheets/dummy_spreadsheet:getByDataFilter", func(w http.ResponseWriter, _ *http.Request) {
+		cellData++
+		if cellData == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"sheets":[{"data":[{"rowData":[
+			{"values":[{"effectiveValue":{"numberValue":11}}]},
+			{"values":[{"effectiveValue":{"numberValue":12}}]}
+		]}]}]}`))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	reader, err := NewMultiReader(context.Background(), MultiReaderArgs{
+		BatchReaderArgs: BatchReaderArgs{
+			ClientArgs: ClientArgs{
+				TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+				Endpoint:    testServer.URL,
+			},
+			SpreadsheetID: "dummy_spreadsheet",
+			TypedValues:   true,
+			BatchSize:     2,
+		},
+	})
+	assert.NoError(t, err)
+
+	// the rate limited cell data request is retried on the next poll
+	recs, err := reader.GetSheetRecords(context.Background(), map[int64]int64{0: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, recs)
+	assert.False(t, reader.MorePages())
+
+	reader.nextRun = time.Time{}
+	recs, err = reader.GetSheetRecords(context.Background(), map[int64]int64{0: 10})
+	assert.NoError(t, err)
+	assert.Len(t, recs, 2)
+	assert.Equal(t, opencdc.RawData(`[11]`), recs[0].Payload.After)
+	// the page isn't skipped, it's fetched again from the same row
+	assert.Equal(t, []*sheets.GridRange{
+		{StartRowIndex: 10, EndRowIndex: 12},
+		{StartRowIndex: 10, EndRowIndex: 12},
+	}, valueRanges)
+}
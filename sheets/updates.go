@@ -0,0 +1,191 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/position"
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/state"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// SheetID returns the gid of the sheet, resolved from the spreadsheet metadata if it wasn't set in the args
+func (b *BatchReader) SheetID() int64 {
+	return b.sheetID
+}
+
+// SetRowStates sets the last known content of the rows, e.g. loaded from the state file,
+// used to detect the updated rows
+func (b *BatchReader) SetRowStates(rows map[int64]state.Row) {
+	b.rowStates = maps.Clone(rows)
+	if b.rowStates == nil {
+		b.rowStates = make(map[int64]state.Row)
+	}
+}
+
+// RowStates returns a copy of the last known content of the rows, as of the last GetSheetRecords call
+func (b *BatchReader) RowStates() map[int64]state.Row {
+	return maps.Clone(b.rowStates)
+}
+
+// detectRowChanges filters the records of the whole sheet, keeping the rows after the offset as new rows,
+// the known rows whose content changed since the last poll as OperationUpdate records if detectUpdates is enabled,
+// and adding OperationDelete records for the known rows not found anymore if detectDeletes is enabled.
+// The rows are matched by their identity, the identity column value or key in the rowIdentities, or the row number.
+// The update records keep the position of their row, and the delete records have the offset as position
+// along with the row number of the removed row, so each record has its own position, while the row offsets
+// of the sheets set by setSheetOffsets never go back to an already read row.
+// The current row states are returned, the row states of the reader being left unchanged.
+func (b *BatchReader) detectRowChanges(
+	records []opencdc.Record,
+	rowIdentities map[int64]string,
+	offset int64,
+) ([]opencdc.Record, map[int64]state.Row, error) {
+	// when the row states are empty, e.g. on the first run with change detection enabled, or don't have
+	// the identities of the rows, the rows up to the offset are the baseline of the next polls, and aren't emitted
+	baseline := len(b.rowStates) == 0
+	previousRows := make(map[string]int64, len(b.rowStates))
+	for rowNumber, row := range b.rowStates {
+		if b.hasRowIdentity() && row.Identity == "" {
+			baseline = true
+		}
+		previousRows[b.stateIdentity(rowNumber, row)] = rowNumber
+	}
+	currentRows := make(map[int64]state.Row, len(records))
+	seen := make(map[string]bool, len(records))
+	changes := make([]opencdc.Record, 0)
+	for _, record := range records {
+		pos, err := position.ParseRecordPosition(record.Position)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse record position: %w", err)
+		}
+		rowNumber := pos.RowOffset
+
+		current := state.NewRow(record.Payload.After.Bytes())
+		if b.hasRowIdentity() {
+			current.Identity = rowIdentity(rowIdentities, rowNumber)
+		}
+		if len(b.keyColumns) > 0 {
+			current.Key = record.Key.Bytes()
+		}
+		currentRows[rowNumber] = current
+		identity := b.stateIdentity(rowNumber, current)
+		seen[identity] = true
+
+		previousRowNumber, known := previousRows[identity]
+		previous := b.rowStates[previousRowNumber]
+		switch {
+		case rowNumber > offset:
+			changes = append(changes, record)
+		case baseline:
+			continue
+		case !known:
+			// a row inserted or filled after the offset went past it, emitted as a new row
+			changes = append(changes, record)
+		case b.detectUpdates && previous.Fingerprint != current.Fingerprint:
+			before, err := b.statePayload(previous)
+			if err != nil {
+				return nil, nil, err
+			}
+			record.Operation = opencdc.OperationUpdate
+			record.Payload.Before = before
+			changes = append(changes, record)
+		}
+	}
+
+	if b.detectDeletes && !baseline {
+		deletes, err := b.deletedRows(previousRows, seen, offset)
+		if err != nil {
+			return nil, nil, err
+		}
+		// the deletes come first, keeping the positions of the records in order
+		changes = append(deletes, changes...)
+	}
+
+	return changes, currentRows, nil
+}
+
+// deletedRows returns the OperationDelete records of the known rows not seen anymore, in the order of their last row number
+func (b *BatchReader) deletedRows(previousRows map[string]int64, seen map[string]bool, offset int64) ([]opencdc.Record, error) {
+	rowNumbers := make([]int64, 0)
+	for identity, rowNumber := range previousRows {
+		if !seen[identity] {
+			rowNumbers = append(rowNumbers, rowNumber)
+		}
+	}
+	slices.Sort(rowNumbers)
+
+	deletes := make([]opencdc.Record, 0, len(rowNumbers))
+	for _, rowNumber := range rowNumbers {
+		before, err := b.statePayload(b.rowStates[rowNumber])
+		if err != nil {
+			return nil, err
+		}
+		var key opencdc.Data = opencdc.RawData(fmt.Sprintf("%d", rowNumber))
+		if stateKey := b.rowStates[rowNumber].Key; len(stateKey) > 0 {
+			structuredKey := make(opencdc.StructuredData)
+			if err := json.Unmarshal(stateKey, &structuredKey); err != nil {
+				return nil, fmt.Errorf("unable to unmarshal the row state key: %w", err)
+			}
+			key = structuredKey
+		}
+		pos := position.SheetPosition{
+			RowOffset:     offset,
+			SpreadsheetID: b.spreadsheetID,
+			SheetID:       b.sheetID,
+			DeletedRow:    rowNumber,
+		}
+		deletes = append(deletes, sdk.Util.Source.NewRecordDelete(pos.RecordPosition(), b.rowMetadata(rowNumber), key, before))
+	}
+	return deletes, nil
+}
+
+// hasRowIdentity returns whether the rows are identified by a column value, the identity column or the key columns
+func (b *BatchReader) hasRowIdentity() bool {
+	return b.identityColumn != "" || len(b.keyColumns) > 0
+}
+
+// rowIdentity returns the identity column value, or the key, of the row, or its row number if the identity cell is blank
+func rowIdentity(rowIdentities map[int64]string, rowNumber int64) string {
+	if identity := rowIdentities[rowNumber]; identity != "" {
+		return identity
+	}
+	return fmt.Sprintf("#%d", rowNumber)
+}
+
+// stateIdentity returns the identity of the row state, its row number if the rows aren't identified by a column
+func (b *BatchReader) stateIdentity(rowNumber int64, row state.Row) string {
+	if b.hasRowIdentity() && row.Identity != "" {
+		return row.Identity
+	}
+	return fmt.Sprintf("#%d", rowNumber)
+}
+
+// statePayload returns the record payload of the row state, structured data if the records are structured
+func (b *BatchReader) statePayload(row state.Row) (opencdc.Data, error) {
+	if b.headerRow == 0 {
+		return opencdc.RawData(row.Payload), nil
+	}
+	data := make(opencdc.StructuredData)
+	if err := json.Unmarshal(row.Payload, &data); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal the row state payload: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,141 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sheets
+
+import (
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/state"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestBatchReader_detectRowChanges(t *testing.T) {
+	br := &BatchReader{
+		spreadsheetID: "dummy_spreadsheet",
+		sheetID:       1234,
+		detectUpdates: true,
+	}
+	br.SetRowStates(map[int64]state.Row{
+		1: state.NewRow([]byte(`["a","1"]`)),
+		2: state.NewRow([]byte(`["b","2"]`)),
+	})
+
+	records, page, err := br.valueRangesToRecords([]*sheets.MatchedValueRange{{ValueRange: &sheets.ValueRange{
+		Values: [][]any{{"a", "1"}, {"b", "changed"}, {"c", "3"}, {"d", "4"}},
+	}}}, nil, 0)
+	assert.NoError(t, err)
+
+	out, rows, err := br.detectRowChanges(records, page.identities, 3)
+	assert.NoError(t, err)
+	assert.Len(t, out, 3)
+
+	// row 2 changed
+	assert.Equal(t, opencdc.OperationUpdate, out[0].Operation)
+	assert.Equal(t, opencdc.RawData(`2`), out[0].Key)
+	assert.Equal(t, opencdc.RawData(`["b","2"]`), out[0].Payload.Before)
+	assert.Equal(t, opencdc.RawData(`["b","changed"]`), out[0].Payload.After)
+	assert.Equal(t, opencdc.Position(`{"row_offset":2,"spreadsheet_id":"dummy_spreadsheet","sheet_id":1234}`), out[0].Position)
+
+	// row 3 is before the offset, without a known content
+	assert.Equal(t, opencdc.OperationSnapshot, out[1].Operation)
+	assert.Equal(t, opencdc.Position(`{"row_offset":3,"spreadsheet_id":"dummy_spreadsheet","sheet_id":1234}`), out[1].Position)
+
+	// row 4 is a new row
+	assert.Equal(t, opencdc.OperationSnapshot, out[2].Operation)
+	assert.Equal(t, opencdc.Position(`{"row_offset":4,"spreadsheet_id":"dummy_spreadsheet","sheet_id":1234}`), out[2].Position)
+
+	assert.Equal(t, map[int64]state.Row{
+		1: state.NewRow([]byte(`["a","1"]`)),
+		2: state.NewRow([]byte(`["b","changed"]`)),
+		3: state.NewRow([]byte(`["c","3"]`)),
+		4: state.NewRow([]byte(`["d","4"]`)),
+	}, rows)
+	// the row states of the reader are only set once the records of all the sheets are built
+	assert.Len(t, br.RowStates(), 2)
+}
+
+func TestBatchReader_detectRowChanges_Baseline(t *testing.T) {
+	br := &BatchReader{
+		spreadsheetID: "dummy_spreadsheet",
+		sheetID:       1234,
+		headerRow:     1,
+		detectUpdates: true,
+	}
+	br.SetRowStates(nil)
+
+	records, page, err := br.valueRangesToRecords([]*sheets.MatchedValueRange{
+		{ValueRange: &sheets.ValueRange{Values: [][]any{{"name"}}}},
+		{ValueRange: &sheets.ValueRange{Values: [][]any{{"a"}, {"b"}}}},
+	}, nil, 1)
+	assert.NoError(t, err)
+
+	// without known row contents, the rows up to the offset are only recorded
+	out, rows, err := br.detectRowChanges(records, page.identities, 2)
+	assert.NoError(t, err)
+	assert.Len(t, out, 1)
+	assert.Equal(t, opencdc.StructuredData{"name": "b"}, out[0].Payload.After)
+	br.SetRowStates(rows)
+
+	records[0].Payload.After = opencdc.StructuredData{"name": "changed"}
+	out, _, err = br.detectRowChanges(records[:1], page.identities, 3)
+	assert.NoError(t, err)
+	assert.Len(t, out, 1)
+	assert.Equal(t, opencdc.OperationUpdate, out[0].Operation)
+	assert.Equal(t, opencdc.StructuredData{"name": "a"}, out[0].Payload.Before)
+	assert.Equal(t, opencdc.StructuredData{"name": "changed"}, out[0].Payload.After)
+}
+
+func TestBatchReader_detectRowChanges_Deletes(t *testing.T) {
+	br := &BatchReader{
+		spreadsheetID:  "dummy_spreadsheet",
+		sheetID:        1234,
+		headerRow:      1,
+		detectUpdates:  true,
+		detectDeletes:  true,
+		identityColumn: "id",
+	}
+	br.SetRowStates(map[int64]state.Row{
+		2: {Fingerprint: state.Fingerprint([]byte(`{"id":"1","name":"a"}`)), Payload: []byte(`{"id":"1","name":"a"}`), Identity: "1"},
+		3: {Fingerprint: state.Fingerprint([]byte(`{"id":"2","name":"b"}`)), Payload: []byte(`{"id":"2","name":"b"}`), Identity: "2"},
+		4: {Fingerprint: state.Fingerprint([]byte(`{"id":"3","name":"c"}`)), Payload: []byte(`{"id":"3","name":"c"}`), Identity: "3"},
+	})
+
+	// row 2 was removed, the rows below shifted up, and row 3 changed
+	records, page, err := br.valueRangesToRecords([]*sheets.MatchedValueRange{
+		{ValueRange: &sheets.ValueRange{Values: [][]any{{"id", "name"}}}},
+		{ValueRange: &sheets.ValueRange{Values: [][]any{{"1", "a"}, {"3", "changed"}}}},
+	}, nil, 1)
+	assert.NoError(t, err)
+
+	out, rows, err := br.detectRowChanges(records, page.identities, 4)
+	assert.NoError(t, err)
+	assert.Len(t, out, 2)
+
+	assert.Equal(t, opencdc.OperationDelete, out[0].Operation)
+	assert.Equal(t, opencdc.RawData(`3`), out[0].Key)
+	assert.Equal(t, opencdc.StructuredData{"id": "2", "name": "b"}, out[0].Payload.Before)
+	assert.Equal(t, opencdc.Position(`{"row_offset":4,"spreadsheet_id":"dummy_spreadsheet","sheet_id":1234,"deleted_row":3}`), out[0].Position)
+
+	assert.Equal(t, opencdc.OperationUpdate, out[1].Operation)
+	assert.Equal(t, opencdc.StructuredData{"id": "3", "name": "c"}, out[1].Payload.Before)
+	assert.Equal(t, opencdc.StructuredData{"id": "3", "name": "changed"}, out[1].Payload.After)
+	assert.Equal(t, opencdc.Position(`{"row_offset":3,"spreadsheet_id":"dummy_spreadsheet","sheet_id":1234}`), out[1].Position)
+
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "1", rows[2].Identity)
+	assert.Equal(t, "3", rows[3].Identity)
+}
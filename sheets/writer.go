@@ -24,9 +24,7 @@ import (
 
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
-	"golang.org/x/oauth2"
 	"google.golang.org/api/googleapi"
-	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
 )
 
@@ -47,23 +45,39 @@ type Writer struct {
 	retryCount int64
 }
 
-func NewWriter(
-	ctx context.Context,
-	oauthCfg *oauth2.Config,
-	token *oauth2.Token,
-	spreadsheetID, sheetName, valueInputOption string,
-	retries int64,
-) (*Writer, error) {
-	sheetService, err := sheets.NewService(ctx, option.WithHTTPClient(oauthCfg.Client(ctx, token)))
+type WriterArgs struct {
+	ClientArgs
+	SpreadsheetID string
+	// SheetID is the gid of the sheet, negative if unknown, in which case the sheet is located by the SheetName
+	// or the first sheet of the spreadsheet is used
+	SheetID          int64
+	SheetName        string
+	ValueInputOption string
+	MaxRetries       int64
+}
+
+func NewWriter(ctx context.Context, args WriterArgs) (*Writer, error) {
+	sheetService, err := newService(ctx, args.ClientArgs)
 	if err != nil {
-		return nil, fmt.Errorf("error creating sheets(%s) service client: %w", sheetName, err)
+		return nil, err
 	}
+
+	sheetName := args.SheetName
+	// resolve the sheet name from the gid, also validating the gid and the name match if both are set
+	if args.SheetID >= 0 || sheetName == "" {
+		_, sheetProperties, err := resolveSheet(ctx, sheetService, args.SpreadsheetID, args.SheetID, args.SheetName)
+		if err != nil {
+			return nil, err
+		}
+		sheetName = sheetProperties.Title
+	}
+
 	return &Writer{
-		spreadsheetID:    spreadsheetID,
+		spreadsheetID:    args.SpreadsheetID,
 		sheetSvc:         sheetService,
 		sheetName:        sheetName,
-		valueInputOption: valueInputOption,
-		maxRetries:       retries,
+		valueInputOption: args.ValueInputOption,
+		maxRetries:       args.MaxRetries,
 	}, nil
 }
 
@@ -103,15 +117,17 @@ func (w *Writer) Write(ctx context.Context, records []opencdc.Record) (int, erro
 	// KeyValueInputOption is the config name for how the input data
 	// should be interpreted.
 	// Creating a google-sheet format to append to google-sheet
+	// The sheet title is quoted, so a title like Q1 isn't read as a cell of the first sheet
+	sheetRange := quoteSheetTitle(w.sheetName)
 
 	sheetValueFormat := &sheets.ValueRange{
 		MajorDimension: majorDimension,
-		Range:          w.sheetName,
+		Range:          sheetRange,
 		Values:         rows,
 	}
 
 	_, err := w.sheetSvc.Spreadsheets.Values.Append(
-		w.spreadsheetID, w.sheetName,
+		w.spreadsheetID, sheetRange,
 		sheetValueFormat).ValueInputOption(
 		w.valueInputOption).InsertDataOption(
 		insertDataOption).Context(ctx).Do()
@@ -16,6 +16,7 @@ package sheets
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -31,7 +32,13 @@ import (
 func TestWriter_NoRecord(t *testing.T) {
 	ctx := context.Background()
 
-	writer, err := NewWriter(ctx, &oauth2.Config{}, &oauth2.Token{}, "dummy_spreadsheet_id", "Sheet", "", 3)
+	writer, err := NewWriter(ctx, WriterArgs{
+		ClientArgs:    ClientArgs{TokenSource: oauth2.StaticTokenSource(&oauth2.Token{})},
+		SpreadsheetID: "dummy_spreadsheet_id",
+		SheetID:       -1,
+		SheetName:     "Sheet",
+		MaxRetries:    3,
+	})
 	assert.NoError(t, err)
 
 	i, err := writer.Write(ctx, nil)
@@ -98,3 +105,31 @@ func TestWriter_429(t *testing.T) {
 	assert.EqualError(t, err, "rate limit exceeded, retries: 2, error: googleapi: got HTTP response code 429 with body: {}")
 	assert.Equal(t, 0, i)
 }
+
+func TestWriter_QuotesCellReferenceTitle(t *testing.T) {
+	var body sheets.ValueRange
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v4/spreadsheets/dummy/values/'Q1':append", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer testServer.Close()
+
+	sheetSvc, err := sheets.NewService(
+		context.Background(),
+		option.WithEndpoint(testServer.URL),
+		option.WithHTTPClient(&http.Client{}))
+	assert.NoError(t, err)
+
+	writer := &Writer{
+		sheetSvc:         sheetSvc,
+		sheetName:        "Q1",
+		spreadsheetID:    "dummy",
+		valueInputOption: "USER_ENTERED",
+	}
+
+	i, err := writer.Write(context.Background(), []opencdc.Record{{Payload: opencdc.Change{After: opencdc.RawData(`["1","2"]`)}}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+	assert.Equal(t, "'Q1'", body.Range)
+}
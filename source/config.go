@@ -16,10 +16,13 @@ package source
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/conduitio-labs/conduit-connector-google-sheets/config"
+	"github.com/conduitio-labs/conduit-connector-google-sheets/sheets"
 )
 
 const (
@@ -28,11 +31,102 @@ const (
 	KeyDateTimeRenderOption = "dateTimeRenderOption"
 	KeyValueRenderOption    = "valueRenderOption"
 
+	// KeyHeaderRow is the config name for the 1-based row number of the header row,
+	// providing the field names of the structured records
+	KeyHeaderRow = "headerRow"
+
+	// KeyTypedValues is the config name for converting the cell values to their types
+	// using the cells' effective value and number format
+	KeyTypedValues = "typedValues"
+
+	// KeyDetectUpdates is the config name for emitting the changes of the already read rows as update records
+	KeyDetectUpdates = "detectUpdates"
+
+	// KeyDetectDeletes is the config name for emitting the removed rows as delete records
+	KeyDetectDeletes = "detectDeletes"
+
+	// KeyRowIdentityColumn is the config name for the header name or the column letter of the column identifying the rows
+	KeyRowIdentityColumn = "rowIdentityColumn"
+
+	// KeyKeyColumns is the config name for the comma separated header names or column letters of the columns
+	// holding the record keys
+	KeyKeyColumns = "keyColumns"
+
+	// KeyEmptyKeyPolicy is the config name for handling the rows with a blank key cell
+	KeyEmptyKeyPolicy = "emptyKeyPolicy"
+
+	// KeyStateFile is the config name for the local file persisting the last known content of the rows
+	KeyStateFile = "stateFile"
+
+	// KeySheets is the config name for the comma separated titles, or gid=<gid>, of the sheets(tabs) to read
+	KeySheets = "sheets"
+
+	// KeyAllSheets is the config name for reading all the sheets(tabs) of the spreadsheet, including the added ones
+	KeyAllSheets = "allSheets"
+
+	// KeyIncludeSheets is the config name for the regular expression the titles of the sheets read with allSheets must match
+	KeyIncludeSheets = "includeSheets"
+
+	// KeyExcludeSheets is the config name for the regular expression the titles of the sheets read with allSheets mustn't match
+	KeyExcludeSheets = "excludeSheets"
+
+	// KeySheetsRefreshPeriod is the config name for the period of the discovery of the sheets read with allSheets,
+	// and of the spreadsheets of the driveFolderID folder
+	KeySheetsRefreshPeriod = "sheetsRefreshPeriod"
+
+	// KeyCreatedAtColumn is the config name for the header name or the column letter of the column
+	// holding the records created-at time
+	KeyCreatedAtColumn = "createdAtColumn"
+
+	// KeyCreatedAtLayout is the config name for the Go time layout of the created-at cells
+	KeyCreatedAtLayout = "createdAtLayout"
+
+	// KeyCreatedAtTimezone is the config name for the IANA time zone of the created-at cells without zone
+	KeyCreatedAtTimezone = "createdAtTimezone"
+
+	// KeyRange is the config name for the range of the cells read in A1 notation, e.g. B:F or Data!A2:K
+	KeyRange = "range"
+
+	// KeyStartColumnIndex is the config name for the 0-based index of the first column read, an alternative to KeyRange
+	KeyStartColumnIndex = "startColumnIndex"
+
+	// KeyEndColumnIndex is the config name for the 0-based index of the column after the last column read,
+	// an alternative to KeyRange
+	KeyEndColumnIndex = "endColumnIndex"
+
+	// KeyNamedRange is the config name for the named range read instead of a sheet, followed wherever it's moved
+	KeyNamedRange = "namedRange"
+
+	// KeyBatchSize is the config name for the maximum number of rows fetched per request, the next rows of a large sheet
+	// being fetched right away
+	KeyBatchSize = "batchSize"
+
+	// KeySnapshotConcurrency is the config name for the maximum number of pages of batchSize rows fetched concurrently
+	// by the snapshot of the sheets on start, 0 to disable the snapshot
+	KeySnapshotConcurrency = "snapshotConcurrency"
+
+	// KeySnapshotRequestsPerMinute is the config name for the maximum rate of the page requests of the snapshot
+	KeySnapshotRequestsPerMinute = "snapshotRequestsPerMinute"
+
 	// defaultPollingPeriod is the value assumed for the pooling period when the
 	// config omits the polling period parameter
 	defaultPollingPeriod        = "6s"
 	defaultDateTimeRenderOption = "FORMATTED_STRING"
 	defaultValueRenderOption    = "FORMATTED_VALUE"
+	defaultEmptyKeyPolicy       = EmptyKeyPolicyHold
+	defaultSheetsRefreshPeriod  = time.Minute
+	// defaultSnapshotRequestsPerMinute is the default Sheets API read requests quota per minute per user
+	defaultSnapshotRequestsPerMinute = 60
+)
+
+const (
+	// EmptyKeyPolicyHold holds the row offset before the first row with blank key cells, the row and the rows after it
+	// being read again on the next poll, so a row is read once its key is filled
+	EmptyKeyPolicyHold = "hold"
+	// EmptyKeyPolicyError fails reading the sheet on a row with blank key cells
+	EmptyKeyPolicyError = "error"
+	// EmptyKeyPolicySkip skips the rows with blank key cells, a skipped last row is read again on the next poll
+	EmptyKeyPolicySkip = "skip"
 )
 
 // Config represents source configuration with Google-Sheets configurations
@@ -40,18 +134,86 @@ type Config struct {
 	config.Config
 	PollingPeriod time.Duration
 
+	// Sheets are the sheets(tabs) read with a single request per poll, the sheet of the shared config is read when empty
+	Sheets []sheets.SheetLocator
+	// AllSheets reads all the grid sheets of the spreadsheet whose title matches IncludeSheets and doesn't match
+	// ExcludeSheets, if set, discovering the added and removed sheets every SheetsRefreshPeriod
+	AllSheets           bool
+	IncludeSheets       *regexp.Regexp
+	ExcludeSheets       *regexp.Regexp
+	SheetsRefreshPeriod time.Duration
+
 	// google sheets data fetch options.
 	// Refer: https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets.values/batchGet#query-parameters
 	DateTimeRenderOption string // values: SERIAL_NUMBER, FORMATTED_STRING // default: SERIAL_NUMBER
 	ValueRenderOption    string // values: FORMATTED_VALUE, UNFORMATTED_VALUE, FORMULA// default: FORMATTED_VALUE
+
+	// HeaderRow is the 1-based row number of the header row, 0 when the rows are emitted as JSON arrays
+	HeaderRow int64
+
+	// TypedValues converts the cell values to int64, float64, bool, or time.Time with the SERIAL_NUMBER dateTimeRenderOption
+	TypedValues bool
+
+	// DetectUpdates scans the whole sheet on each poll to emit the changed rows as update records,
+	// the last known content of the rows is persisted in the StateFile
+	DetectUpdates bool
+	StateFile     string
+	// DetectDeletes scans the whole sheet on each poll to emit the removed rows as delete records,
+	// the rows being followed by the value of their RowIdentityColumn
+	DetectDeletes     bool
+	RowIdentityColumn string
+
+	// KeyColumns are the header names or column letters of the columns holding the record keys, the row number when empty
+	KeyColumns []string
+	// EmptyKeyPolicy is the handling of the rows with blank key cells, EmptyKeyPolicyHold, EmptyKeyPolicyError
+	// or EmptyKeyPolicySkip
+	EmptyKeyPolicy string
+
+	// CreatedAtColumn is the header name or the column letter of the column holding the records created-at time,
+	// parsed with the CreatedAtLayout, in the CreatedAtLocation or the spreadsheet time zone if nil
+	CreatedAtColumn   string
+	CreatedAtLayout   string
+	CreatedAtLocation *time.Location
+
+	// Range is the range of the cells read in the sheets, the whole sheets if zero,
+	// the sheet name of the range being moved to the GoogleSheetName
+	Range sheets.CellRange
+	// NamedRange is the named range read instead of the sheet, the HeaderRow and the positions being relative to the range
+	NamedRange string
+
+	// BatchSize is the maximum number of rows fetched per request, 0 to fetch all the rows after the offsets
+	BatchSize int64
+	// Snapshot are the options of the parallel snapshot of the sheets on start, fetching pages of BatchSize rows
+	Snapshot sheets.SnapshotArgs
 }
 
 // Parse attempts to parse the configurations into a Config struct that Source could utilize
 func Parse(cfg map[string]string) (Config, error) {
-	commonConfig, err := config.Parse(cfg)
+	// the source only reads the spreadsheet, request the read-only scope
+	commonConfig, err := config.Parse(cfg, config.ScopeSpreadsheetsReadOnly)
 	if err != nil {
 		return Config{}, fmt.Errorf("error parsing shared config, %w", err)
 	}
+
+	sourceConfig := Config{Config: commonConfig}
+	for _, parse := range []func(map[string]string) error{
+		sourceConfig.parseRenderOptions,
+		sourceConfig.parseRowShape,
+		sourceConfig.parseChangeDetection,
+		sourceConfig.parseSheetSelection,
+		sourceConfig.parseRanges,
+		sourceConfig.parseBatching,
+		sourceConfig.parseCreatedAt,
+	} {
+		if err := parse(cfg); err != nil {
+			return Config{}, err
+		}
+	}
+	return sourceConfig, nil
+}
+
+// parseRenderOptions parses the polling period and the render options of the fetched values
+func (c *Config) parseRenderOptions(cfg map[string]string) error {
 	// Time interval being an optional value
 	interval := strings.TrimSpace(cfg[KeyPollingPeriod])
 	if interval == "" {
@@ -60,7 +222,7 @@ func Parse(cfg map[string]string) (Config, error) {
 
 	timeInterval, err := time.ParseDuration(interval)
 	if err != nil {
-		return Config{}, fmt.Errorf("%q cannot parse interval to time duration", interval)
+		return fmt.Errorf("%q cannot parse interval to time duration", interval)
 	}
 
 	dateTimeOption := strings.TrimSpace(cfg[KeyDateTimeRenderOption])
@@ -68,7 +230,7 @@ func Parse(cfg map[string]string) (Config, error) {
 		dateTimeOption = defaultDateTimeRenderOption
 	}
 	if dateTimeOption != "SERIAL_NUMBER" && dateTimeOption != "FORMATTED_STRING" {
-		return Config{}, fmt.Errorf(
+		return fmt.Errorf(
 			"invalid value received for config(`%s`):`%s`, should be oneof [`SERIAL_NUMBER`, `FORMATTED_STRING`]",
 			KeyDateTimeRenderOption, dateTimeOption,
 		)
@@ -79,18 +241,312 @@ func Parse(cfg map[string]string) (Config, error) {
 		valueOption = defaultValueRenderOption
 	}
 	if valueOption != "FORMATTED_VALUE" && valueOption != "UNFORMATTED_VALUE" && valueOption != "FORMULA" {
-		return Config{}, fmt.Errorf(
+		return fmt.Errorf(
 			"invalid value received for config(`%s`):`%s`, should be oneof [`FORMATTED_VALUE`, `UNFORMATTED_VALUE`, `FORMULA`]",
 			KeyValueRenderOption, valueOption,
 		)
 	}
 
-	sourceConfig := Config{
-		Config:               commonConfig,
-		PollingPeriod:        timeInterval,
-		DateTimeRenderOption: dateTimeOption,
-		ValueRenderOption:    valueOption,
+	c.PollingPeriod = timeInterval
+	c.DateTimeRenderOption = dateTimeOption
+	c.ValueRenderOption = valueOption
+	return nil
+}
+
+// parseRowShape parses the options shaping the records of the rows, the header row, the typed values and the keys
+func (c *Config) parseRowShape(cfg map[string]string) error {
+	var err error
+	if headerRowStr := strings.TrimSpace(cfg[KeyHeaderRow]); headerRowStr != "" {
+		c.HeaderRow, err = strconv.ParseInt(headerRowStr, 10, 64)
+		if err != nil || c.HeaderRow < 0 {
+			return fmt.Errorf("%q config value should be a non-negative integer", KeyHeaderRow)
+		}
 	}
 
-	return sourceConfig, nil
+	c.TypedValues, err = parseBool(cfg, KeyTypedValues)
+	if err != nil {
+		return err
+	}
+	if c.TypedValues && c.ValueRenderOption == "FORMULA" {
+		return fmt.Errorf("%q config can't be used with the `FORMULA` %q", KeyTypedValues, KeyValueRenderOption)
+	}
+
+	for _, column := range strings.Split(cfg[KeyKeyColumns], ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			c.KeyColumns = append(c.KeyColumns, column)
+		}
+	}
+	c.EmptyKeyPolicy = strings.TrimSpace(cfg[KeyEmptyKeyPolicy])
+	if c.EmptyKeyPolicy == "" {
+		c.EmptyKeyPolicy = defaultEmptyKeyPolicy
+	}
+	switch c.EmptyKeyPolicy {
+	case EmptyKeyPolicyHold, EmptyKeyPolicyError, EmptyKeyPolicySkip:
+	default:
+		return fmt.Errorf(
+			"invalid value received for config(`%s`):`%s`, should be oneof [`hold`, `error`, `skip`]",
+			KeyEmptyKeyPolicy, c.EmptyKeyPolicy,
+		)
+	}
+	return nil
+}
+
+// parseChangeDetection parses the detection of the updated and removed rows, and the state file they require
+func (c *Config) parseChangeDetection(cfg map[string]string) error {
+	var err error
+	c.DetectUpdates, err = parseBool(cfg, KeyDetectUpdates)
+	if err != nil {
+		return err
+	}
+	c.DetectDeletes, err = parseBool(cfg, KeyDetectDeletes)
+	if err != nil {
+		return err
+	}
+
+	c.RowIdentityColumn = strings.TrimSpace(cfg[KeyRowIdentityColumn])
+	if c.DetectDeletes && c.RowIdentityColumn == "" && len(c.KeyColumns) == 0 {
+		return fmt.Errorf("%q or %q config value must be set when %q is enabled", KeyRowIdentityColumn, KeyKeyColumns, KeyDetectDeletes)
+	}
+	c.StateFile = strings.TrimSpace(cfg[KeyStateFile])
+	if c.DetectUpdates && c.StateFile == "" {
+		return fmt.Errorf("%q config value must be set when %q is enabled", KeyStateFile, KeyDetectUpdates)
+	}
+	if c.DetectDeletes && c.StateFile == "" {
+		return fmt.Errorf("%q config value must be set when %q is enabled", KeyStateFile, KeyDetectDeletes)
+	}
+	return nil
+}
+
+// parseSheetSelection parses the sheets(tabs) read, listed or discovered with allSheets
+func (c *Config) parseSheetSelection(cfg map[string]string) error {
+	var err error
+	c.Sheets, err = parseSheets(cfg)
+	if err != nil {
+		return err
+	}
+
+	c.AllSheets, err = parseBool(cfg, KeyAllSheets)
+	if err != nil {
+		return err
+	}
+	if c.AllSheets && (len(c.Sheets) > 0 ||
+		strings.TrimSpace(cfg[config.KeySheetID]) != "" || strings.TrimSpace(cfg[config.KeySheetName]) != "") {
+		return fmt.Errorf("%q config can't be used with %q, %q or %q", KeyAllSheets, KeySheets, config.KeySheetID, config.KeySheetName)
+	}
+	c.IncludeSheets, err = parseRegexp(cfg, KeyIncludeSheets, c.AllSheets)
+	if err != nil {
+		return err
+	}
+	c.ExcludeSheets, err = parseRegexp(cfg, KeyExcludeSheets, c.AllSheets)
+	if err != nil {
+		return err
+	}
+	c.SheetsRefreshPeriod = defaultSheetsRefreshPeriod
+	if refreshPeriod := strings.TrimSpace(cfg[KeySheetsRefreshPeriod]); refreshPeriod != "" {
+		c.SheetsRefreshPeriod, err = time.ParseDuration(refreshPeriod)
+		if err != nil || c.SheetsRefreshPeriod <= 0 {
+			return fmt.Errorf("%q config value should be a positive duration, e.g. 1m", KeySheetsRefreshPeriod)
+		}
+	}
+	return nil
+}
+
+// parseRanges parses the range of the cells read in the sheets, or the named range read instead of the sheets
+func (c *Config) parseRanges(cfg map[string]string) error {
+	cellRange, err := parseRange(cfg)
+	if err != nil {
+		return err
+	}
+	if cellRange.SheetName != "" {
+		if len(c.Sheets) > 0 || c.AllSheets {
+			return fmt.Errorf("%q config value can't hold a sheet name with %q or %q", KeyRange, KeySheets, KeyAllSheets)
+		}
+		if c.GoogleSheetName != "" && c.GoogleSheetName != cellRange.SheetName {
+			return fmt.Errorf("%q config value sheet(%q) doesn't match the %q config value(%q)",
+				KeyRange, cellRange.SheetName, config.KeySheetName, c.GoogleSheetName)
+		}
+		// the sheet of the range is located by its name
+		c.GoogleSheetName = cellRange.SheetName
+		cellRange.SheetName = ""
+	}
+	c.Range = cellRange
+
+	c.NamedRange = strings.TrimSpace(cfg[KeyNamedRange])
+	if c.NamedRange != "" {
+		for _, key := range []string{
+			KeyRange, KeyStartColumnIndex, KeyEndColumnIndex, KeySheets, KeyAllSheets, config.KeySheetID, config.KeySheetName,
+		} {
+			if strings.TrimSpace(cfg[key]) != "" {
+				return fmt.Errorf("%q config value can't be used with %q", KeyNamedRange, key)
+			}
+		}
+	}
+	return nil
+}
+
+// parseBatching parses the batch size of the requests, and the parallel snapshot fetching pages of batchSize rows
+func (c *Config) parseBatching(cfg map[string]string) error {
+	var err error
+	if batchSizeStr := strings.TrimSpace(cfg[KeyBatchSize]); batchSizeStr != "" {
+		c.BatchSize, err = strconv.ParseInt(batchSizeStr, 10, 64)
+		if err != nil || c.BatchSize <= 0 {
+			return fmt.Errorf("%q config value should be a positive integer", KeyBatchSize)
+		}
+		// the whole sheet, or the whole named range, is read on each poll
+		switch {
+		case c.DetectUpdates:
+			return fmt.Errorf("%q config value can't be used with %q", KeyBatchSize, KeyDetectUpdates)
+		case c.DetectDeletes:
+			return fmt.Errorf("%q config value can't be used with %q", KeyBatchSize, KeyDetectDeletes)
+		case c.NamedRange != "":
+			return fmt.Errorf("%q config value can't be used with %q", KeyBatchSize, KeyNamedRange)
+		}
+	}
+
+	c.Snapshot, err = parseSnapshot(cfg, c.BatchSize)
+	return err
+}
+
+// parseCreatedAt parses the column holding the records created-at time, along with its layout and time zone
+func (c *Config) parseCreatedAt(cfg map[string]string) error {
+	c.CreatedAtColumn = strings.TrimSpace(cfg[KeyCreatedAtColumn])
+	// an empty layout is the Forms timestamp layout, applied by the reader
+	c.CreatedAtLayout = strings.TrimSpace(cfg[KeyCreatedAtLayout])
+	if createdAtTimezone := strings.TrimSpace(cfg[KeyCreatedAtTimezone]); createdAtTimezone != "" {
+		var err error
+		c.CreatedAtLocation, err = time.LoadLocation(createdAtTimezone)
+		if err != nil {
+			return fmt.Errorf("%q config value should be an IANA time zone, e.g. Europe/Paris: %w", KeyCreatedAtTimezone, err)
+		}
+	}
+	if c.CreatedAtColumn == "" && (c.CreatedAtLayout != "" || c.CreatedAtLocation != nil) {
+		return fmt.Errorf("%q config value must be set when %q or %q is set", KeyCreatedAtColumn, KeyCreatedAtLayout, KeyCreatedAtTimezone)
+	}
+	return nil
+}
+
+// parseRange parses the optional range of the cells read, either in A1 notation or as start and end column indices
+func parseRange(cfg map[string]string) (sheets.CellRange, error) {
+	a1 := strings.TrimSpace(cfg[KeyRange])
+	startColumn := strings.TrimSpace(cfg[KeyStartColumnIndex])
+	endColumn := strings.TrimSpace(cfg[KeyEndColumnIndex])
+	if a1 != "" {
+		if startColumn != "" || endColumn != "" {
+			return sheets.CellRange{}, fmt.Errorf("%q config value can't be used with %q or %q", KeyRange, KeyStartColumnIndex, KeyEndColumnIndex)
+		}
+		cellRange, err := sheets.ParseA1Range(a1)
+		if err != nil {
+			return sheets.CellRange{}, fmt.Errorf("invalid %q config value: %w", KeyRange, err)
+		}
+		return cellRange, nil
+	}
+
+	var (
+		cellRange sheets.CellRange
+		err       error
+	)
+	if startColumn != "" {
+		cellRange.StartColumn, err = strconv.ParseInt(startColumn, 10, 64)
+		if err != nil || cellRange.StartColumn < 0 {
+			return sheets.CellRange{}, fmt.Errorf("%q config value should be a non-negative integer", KeyStartColumnIndex)
+		}
+	}
+	if endColumn != "" {
+		cellRange.EndColumn, err = strconv.ParseInt(endColumn, 10, 64)
+		if err != nil || cellRange.EndColumn < 0 {
+			return sheets.CellRange{}, fmt.Errorf("%q config value should be a non-negative integer", KeyEndColumnIndex)
+		}
+	}
+	if err := cellRange.Validate(); err != nil {
+		return sheets.CellRange{}, fmt.Errorf("invalid %q and %q config values: %w", KeyStartColumnIndex, KeyEndColumnIndex, err)
+	}
+	return cellRange, nil
+}
+
+// parseSnapshot parses the optional options of the parallel snapshot, which fetches pages of batchSize rows
+func parseSnapshot(cfg map[string]string, batchSize int64) (sheets.SnapshotArgs, error) {
+	var (
+		snapshot sheets.SnapshotArgs
+		err      error
+	)
+	if concurrency := strings.TrimSpace(cfg[KeySnapshotConcurrency]); concurrency != "" {
+		snapshot.Concurrency, err = strconv.Atoi(concurrency)
+		if err != nil || snapshot.Concurrency < 0 {
+			return sheets.SnapshotArgs{}, fmt.Errorf("%q config value should be a non-negative integer", KeySnapshotConcurrency)
+		}
+	}
+	requestsPerMinute := strings.TrimSpace(cfg[KeySnapshotRequestsPerMinute])
+	if snapshot.Concurrency == 0 {
+		if requestsPerMinute != "" {
+			return sheets.SnapshotArgs{}, fmt.Errorf("%q config value must be set when %q is set",
+				KeySnapshotConcurrency, KeySnapshotRequestsPerMinute)
+		}
+		return sheets.SnapshotArgs{}, nil
+	}
+	if batchSize == 0 {
+		return sheets.SnapshotArgs{}, fmt.Errorf("%q config value must be set when %q is set", KeyBatchSize, KeySnapshotConcurrency)
+	}
+
+	snapshot.RequestsPerMinute = defaultSnapshotRequestsPerMinute
+	if requestsPerMinute != "" {
+		snapshot.RequestsPerMinute, err = strconv.Atoi(requestsPerMinute)
+		if err != nil || snapshot.RequestsPerMinute <= 0 {
+			return sheets.SnapshotArgs{}, fmt.Errorf("%q config value should be a positive integer", KeySnapshotRequestsPerMinute)
+		}
+	}
+	return snapshot, nil
+}
+
+// parseSheets parses the optional list of sheets(tabs) to read, by title or by gid=<gid>
+func parseSheets(cfg map[string]string) ([]sheets.SheetLocator, error) {
+	var locators []sheets.SheetLocator
+	for _, sheet := range strings.Split(cfg[KeySheets], ",") {
+		sheet = strings.TrimSpace(sheet)
+		if sheet == "" {
+			continue
+		}
+		gid, ok := strings.CutPrefix(sheet, "gid=")
+		if !ok {
+			locators = append(locators, sheets.SheetLocator{ID: config.NoSheetID, Name: sheet})
+			continue
+		}
+		sheetID, err := strconv.ParseInt(gid, 10, 64)
+		if err != nil || sheetID < 0 {
+			return nil, fmt.Errorf("invalid %q config value %q, the gid should be a non-negative integer", KeySheets, sheet)
+		}
+		locators = append(locators, sheets.SheetLocator{ID: sheetID})
+	}
+	if len(locators) > 0 && (strings.TrimSpace(cfg[config.KeySheetID]) != "" || strings.TrimSpace(cfg[config.KeySheetName]) != "") {
+		return nil, fmt.Errorf("%q config value can't be used with %q or %q", KeySheets, config.KeySheetID, config.KeySheetName)
+	}
+	return locators, nil
+}
+
+// parseRegexp parses the optional regular expression of the sheet titles, only valid with allSheets
+func parseRegexp(cfg map[string]string, name string, allSheets bool) (*regexp.Regexp, error) {
+	value := strings.TrimSpace(cfg[name])
+	if value == "" {
+		return nil, nil
+	}
+	if !allSheets {
+		return nil, fmt.Errorf("%q config value can only be set when %q is enabled", name, KeyAllSheets)
+	}
+	re, err := regexp.Compile(value)
+	if err != nil {
+		return nil, fmt.Errorf("%q config value should be a regular expression: %w", name, err)
+	}
+	return re, nil
+}
+
+// parseBool parses the optional boolean config value, false if not set
+func parseBool(cfg map[string]string, name string) (bool, error) {
+	value := strings.TrimSpace(cfg[name])
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%q config value should be a boolean", name)
+	}
+	return parsed, nil
 }
@@ -18,11 +18,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/conduitio-labs/conduit-connector-google-sheets/config"
+	"github.com/conduitio-labs/conduit-connector-google-sheets/sheets"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,12 +38,14 @@ type sourceTestCase []struct {
 func TestParse(t *testing.T) {
 	filePath := getFilePath("conduit-connector-google-sheets")
 	validCredFile := fmt.Sprintf("%s/testdata/dummy_cred.json", filePath)
+	paris, err := time.LoadLocation("Europe/Paris")
+	assert.NoError(t, err)
 
 	cases := sourceTestCase{
 		{
 			testCase: "Checking against default values",
 			params:   map[string]string{},
-			err:      fmt.Errorf("error parsing shared config, \"credentialsFile\" config value must be set"),
+			err:      fmt.Errorf("error parsing shared config, \"credentialsFile\" or \"credentialsJSON\" config value must be set"),
 			expected: Config{},
 		},
 		{
@@ -52,7 +56,7 @@ func TestParse(t *testing.T) {
 				config.KeySheetURL:        "",
 				KeyPollingPeriod:          "",
 			},
-			err:      fmt.Errorf("error parsing shared config, \"credentialsFile\" config value must be set"),
+			err:      fmt.Errorf("error parsing shared config, \"credentialsFile\" or \"credentialsJSON\" config value must be set"),
 			expected: Config{},
 		},
 		{
@@ -63,7 +67,7 @@ func TestParse(t *testing.T) {
 				config.KeySheetURL:        "",
 				KeyPollingPeriod:          "",
 			},
-			err:      fmt.Errorf("error parsing shared config, \"tokensFile\" config value must be set"),
+			err:      fmt.Errorf("error parsing shared config, \"tokensFile\" or \"tokensJSON\" config value must be set"),
 			expected: Config{},
 		},
 		{
@@ -97,12 +101,670 @@ func TestParse(t *testing.T) {
 			err: nil,
 			expected: Config{
 				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
 					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
 					GoogleSheetID:       158080911,
 				},
 				PollingPeriod:        6 * time.Second,
 				DateTimeRenderOption: defaultDateTimeRenderOption,
 				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+			},
+		},
+		{
+			testCase: "Checking driveFolderID parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeyDriveFolderID:   "https://drive.google.com/drive/folders/1AbCdEfGhIjKlMnOpQrStUvWxYz",
+				config.KeySheetName:       "Expenses",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:        config.AuthModeOAuth,
+					TokensFile:      validCredFile,
+					Scopes:          []string{config.ScopeSpreadsheetsReadOnly, config.ScopeDriveMetadataReadOnly},
+					DriveFolderID:   "1AbCdEfGhIjKlMnOpQrStUvWxYz",
+					GoogleSheetID:   config.NoSheetID,
+					GoogleSheetName: "Expenses",
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+			},
+		},
+		{
+			testCase: "Checking if headerRow parameter is negative",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyHeaderRow:              "-1",
+			},
+			err:      fmt.Errorf("\"headerRow\" config value should be a non-negative integer"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking headerRow parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyHeaderRow:              "1",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+				HeaderRow:            1,
+			},
+		},
+		{
+			testCase: "Checking if typedValues parameter is used with FORMULA valueRenderOption",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyValueRenderOption:      "FORMULA",
+				KeyTypedValues:            "true",
+			},
+			err:      fmt.Errorf("\"typedValues\" config can't be used with the `FORMULA` \"valueRenderOption\""),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking typedValues parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyDateTimeRenderOption:   "SERIAL_NUMBER",
+				KeyTypedValues:            "true",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: "SERIAL_NUMBER",
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+				TypedValues:          true,
+			},
+		},
+		{
+			testCase: "Checking if stateFile parameter is empty with detectUpdates",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyDetectUpdates:          "true",
+			},
+			err:      fmt.Errorf("\"stateFile\" config value must be set when \"detectUpdates\" is enabled"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking detectUpdates parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyDetectUpdates:          "true",
+				KeyStateFile:              "/var/lib/conduit/sheets-state.json",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+				DetectUpdates:        true,
+				StateFile:            "/var/lib/conduit/sheets-state.json",
+			},
+		},
+		{
+			testCase: "Checking if rowIdentityColumn parameter is empty with detectDeletes",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyDetectDeletes:          "true",
+				KeyStateFile:              "/var/lib/conduit/sheets-state.json",
+			},
+			err:      fmt.Errorf("\"rowIdentityColumn\" or \"keyColumns\" config value must be set when \"detectDeletes\" is enabled"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking detectDeletes parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyDetectDeletes:          "true",
+				KeyRowIdentityColumn:      "id",
+				KeyStateFile:              "/var/lib/conduit/sheets-state.json",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+				StateFile:            "/var/lib/conduit/sheets-state.json",
+				DetectDeletes:        true,
+				RowIdentityColumn:    "id",
+			},
+		},
+		{
+			testCase: "Checking if emptyKeyPolicy parameter is invalid",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyEmptyKeyPolicy:         "ignore",
+			},
+			err:      fmt.Errorf("invalid value received for config(`emptyKeyPolicy`):`ignore`, should be oneof [`hold`, `error`, `skip`]"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking keyColumns parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyKeyColumns:             "region, B,",
+				KeyEmptyKeyPolicy:         "skip",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				KeyColumns:           []string{"region", "B"},
+				EmptyKeyPolicy:       EmptyKeyPolicySkip,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+			},
+		},
+		{
+			testCase: "Checking sheets parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+				KeySheets:                 "Orders, gid=1234,",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       config.NoSheetID,
+				},
+				PollingPeriod:        6 * time.Second,
+				Sheets:               []sheets.SheetLocator{{ID: config.NoSheetID, Name: "Orders"}, {ID: 1234}},
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+			},
+		},
+		{
+			testCase: "Checking if sheets parameter has an invalid gid",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+				KeySheets:                 "Orders,gid=abc",
+			},
+			err:      fmt.Errorf("invalid \"sheets\" config value \"gid=abc\", the gid should be a non-negative integer"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking if sheets parameter is used with sheetName",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+				config.KeySheetName:       "Orders",
+				KeySheets:                 "Returns",
+			},
+			err:      fmt.Errorf("\"sheets\" config value can't be used with \"sheetID\" or \"sheetName\""),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking allSheets parameters",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+				KeyAllSheets:              "true",
+				KeyIncludeSheets:          "^2024-",
+				KeyExcludeSheets:          "draft",
+				KeySheetsRefreshPeriod:    "5m",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       config.NoSheetID,
+				},
+				PollingPeriod:        6 * time.Second,
+				AllSheets:            true,
+				IncludeSheets:        regexp.MustCompile("^2024-"),
+				ExcludeSheets:        regexp.MustCompile("draft"),
+				SheetsRefreshPeriod:  5 * time.Minute,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+			},
+		},
+		{
+			testCase: "Checking if allSheets parameter is used with sheets",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+				KeyAllSheets:              "true",
+				KeySheets:                 "Orders",
+			},
+			err:      fmt.Errorf("\"allSheets\" config can't be used with \"sheets\", \"sheetID\" or \"sheetName\""),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking if includeSheets parameter is set without allSheets",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+				KeyIncludeSheets:          "^2024-",
+			},
+			err:      fmt.Errorf("\"includeSheets\" config value can only be set when \"allSheets\" is enabled"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking if excludeSheets parameter is invalid",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+				KeyAllSheets:              "true",
+				KeyExcludeSheets:          "(",
+			},
+			err:      fmt.Errorf("\"excludeSheets\" config value should be a regular expression: error parsing regexp: missing closing ): `(`"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking createdAt parameters",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyCreatedAtColumn:        "Timestamp",
+				KeyCreatedAtLayout:        "2006-01-02 15:04:05",
+				KeyCreatedAtTimezone:      "Europe/Paris",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+				CreatedAtColumn:      "Timestamp",
+				CreatedAtLayout:      "2006-01-02 15:04:05",
+				CreatedAtLocation:    paris,
+			},
+		},
+		{
+			testCase: "Checking if createdAtTimezone parameter is invalid",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyCreatedAtColumn:        "Timestamp",
+				KeyCreatedAtTimezone:      "Mars/Olympus",
+			},
+			err:      fmt.Errorf("\"createdAtTimezone\" config value should be an IANA time zone, e.g. Europe/Paris: unknown time zone Mars/Olympus"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking if createdAtLayout parameter is set without createdAtColumn",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyCreatedAtLayout:        "2006-01-02",
+			},
+			err:      fmt.Errorf("\"createdAtColumn\" config value must be set when \"createdAtLayout\" or \"createdAtTimezone\" is set"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking if the Forms createdAtLayout is set without createdAtColumn",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyCreatedAtLayout:        sheets.DefaultCreatedAtLayout,
+			},
+			err:      fmt.Errorf("\"createdAtColumn\" config value must be set when \"createdAtLayout\" or \"createdAtTimezone\" is set"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking range parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyRange:                  "Data!A2:K",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+					GoogleSheetName:     "Data",
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+				Range:                sheets.CellRange{StartRow: 1, EndColumn: 11},
+			},
+		},
+		{
+			testCase: "Checking column index parameters",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyStartColumnIndex:       "1",
+				KeyEndColumnIndex:         "6",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+				Range:                sheets.CellRange{StartColumn: 1, EndColumn: 6},
+			},
+		},
+		{
+			testCase: "Checking if range parameter is used with column indices",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyRange:                  "B:F",
+				KeyStartColumnIndex:       "1",
+			},
+			err:      fmt.Errorf("\"range\" config value can't be used with \"startColumnIndex\" or \"endColumnIndex\""),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking if range parameter is invalid",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyRange:                  "F:B",
+			},
+			err:      fmt.Errorf("invalid \"range\" config value: invalid range \"F:B\": the end column should be after the start column"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking if range sheet doesn't match sheetName parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				config.KeySheetName:       "Orders",
+				KeyRange:                  "Data!B:F",
+			},
+			err:      fmt.Errorf("\"range\" config value sheet(\"Data\") doesn't match the \"sheetName\" config value(\"Orders\")"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking if column indices parameters are invalid",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyStartColumnIndex:       "6",
+				KeyEndColumnIndex:         "1",
+			},
+			err:      fmt.Errorf("invalid \"startColumnIndex\" and \"endColumnIndex\" config values: the end column should be after the start column"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking namedRange parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyNamedRange:             "OpenOrders",
+				KeyHeaderRow:              "1",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+				HeaderRow:            1,
+				NamedRange:           "OpenOrders",
+			},
+		},
+		{
+			testCase: "Checking if namedRange parameter is used with range",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyNamedRange:             "OpenOrders",
+				KeyRange:                  "B:F",
+			},
+			err:      fmt.Errorf("\"namedRange\" config value can't be used with \"range\""),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking batchSize parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyBatchSize:              "10000",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+				BatchSize:            10000,
+			},
+		},
+		{
+			testCase: "Checking for invalid batchSize parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyBatchSize:              "0",
+			},
+			err:      fmt.Errorf("\"batchSize\" config value should be a positive integer"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking if batchSize parameter is used with namedRange",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit",
+				KeyBatchSize:              "10000",
+				KeyNamedRange:             "OpenOrders",
+			},
+			err:      fmt.Errorf("\"batchSize\" config value can't be used with \"namedRange\""),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking snapshotConcurrency parameter",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyBatchSize:              "10000",
+				KeySnapshotConcurrency:    "4",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
+				BatchSize:            10000,
+				Snapshot:             sheets.SnapshotArgs{Concurrency: 4, RequestsPerMinute: defaultSnapshotRequestsPerMinute},
+			},
+		},
+		{
+			testCase: "Checking if snapshotConcurrency parameter is used without batchSize",
+			params: map[string]string{
+				config.KeyTokensFile:      validCredFile,
+				config.KeyCredentialsFile: validCredFile,
+				config.KeySheetURL:        "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeySnapshotConcurrency:    "4",
+			},
+			err:      fmt.Errorf("\"batchSize\" config value must be set when \"snapshotConcurrency\" is set"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking for invalid snapshotRequestsPerMinute parameter",
+			params: map[string]string{
+				config.KeyTokensFile:         validCredFile,
+				config.KeyCredentialsFile:    validCredFile,
+				config.KeySheetURL:           "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeyBatchSize:                 "10000",
+				KeySnapshotConcurrency:       "4",
+				KeySnapshotRequestsPerMinute: "0",
+			},
+			err:      fmt.Errorf("\"snapshotRequestsPerMinute\" config value should be a positive integer"),
+			expected: Config{},
+		},
+		{
+			testCase: "Checking snapshot parameters default values",
+			params: map[string]string{
+				config.KeyTokensFile:         validCredFile,
+				config.KeyCredentialsFile:    validCredFile,
+				config.KeySheetURL:           "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911",
+				KeySnapshotConcurrency:       "0",
+				KeySnapshotRequestsPerMinute: "",
+			},
+			err: nil,
+			expected: Config{
+				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
+					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
+					GoogleSheetID:       158080911,
+				},
+				PollingPeriod:        6 * time.Second,
+				DateTimeRenderOption: defaultDateTimeRenderOption,
+				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
 			},
 		},
 		{
@@ -116,12 +778,17 @@ func TestParse(t *testing.T) {
 			err: nil,
 			expected: Config{
 				Config: config.Config{
+					AuthMode:            config.AuthModeOAuth,
+					TokensFile:          validCredFile,
+					Scopes:              []string{config.ScopeSpreadsheetsReadOnly},
 					GoogleSpreadsheetID: "19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4",
 					GoogleSheetID:       158080911,
 				},
 				PollingPeriod:        2 * time.Minute,
 				DateTimeRenderOption: defaultDateTimeRenderOption,
 				ValueRenderOption:    defaultValueRenderOption,
+				EmptyKeyPolicy:       defaultEmptyKeyPolicy,
+				SheetsRefreshPeriod:  defaultSheetsRefreshPeriod,
 			},
 		},
 	}
@@ -148,3 +815,20 @@ func getFilePath(path string) string {
 	}
 	return wd
 }
+
+func TestParse_ParameterDefaults(t *testing.T) {
+	filePath := getFilePath("conduit-connector-google-sheets")
+	validCredFile := fmt.Sprintf("%s/testdata/dummy_cred.json", filePath)
+
+	// the declared defaults are applied to the config before it's parsed
+	cfg := map[string]string{}
+	for key, parameter := range (&Source{}).Parameters() {
+		cfg[key] = parameter.Default
+	}
+	cfg[config.KeyTokensFile] = validCredFile
+	cfg[config.KeyCredentialsFile] = validCredFile
+	cfg[config.KeySheetURL] = "https://docs.google.com/spreadsheets/d/19VVe4M-j8MGw-a3B7fcJQnx5JnHjiHf9dwChUkqQ4/edit#gid=158080911"
+
+	_, err := Parse(cfg)
+	assert.NoError(t, err)
+}
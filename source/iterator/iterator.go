@@ -18,83 +18,454 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/conduitio-labs/conduit-connector-google-sheets/sheets"
 	"github.com/conduitio-labs/conduit-connector-google-sheets/source/position"
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/state"
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"gopkg.in/tomb.v2"
 )
 
 type SheetsIterator struct {
-	// sheetsReader is the instance of BatchReader, which is a wrapper calling BatchGet Google sheets API
-	sheetsReader *sheets.BatchReader
-	// rowOffset is the row number of the last fetched row
-	rowOffset int64
+	// readers are the readers of the spreadsheets, polled round-robin, one spreadsheet per tick
+	readers []*spreadsheetReader
+	// next is the index of the reader polled on the next tick
+	next int
 	// tomb is used to manage the go routines lifecycle
 	tomb *tomb.Tomb
 	// ticker is used to poll for new data in regular intervals
 	ticker *time.Ticker
+	// discoveries receives the sheets(tabs) discovered by refreshSheets, applied by startIterator between two polls
+	discoveries chan sheetsDiscovery
+	// folder lists the spreadsheets of the Drive folder read, read with a copy of the folderArgs, nil if no folder is read
+	folder     *sheets.DriveFolder
+	folderArgs sheets.MultiReaderArgs
+	// files receives the readers of the spreadsheets of the folder found by refreshSheets, applied by startIterator
+	files chan driveFilesChange
+	// snapshotArgs are the options of the parallel snapshot of the spreadsheets read at start, before polling them
+	snapshotArgs sheets.SnapshotArgs
 	// caches keeps the slice of records fetched from one google sheet API call
 	caches chan []opencdc.Record
 	// buffer is subscribed by Next function to read for new data
 	// and block till new data becomes available, in case all the records have been read
 	buffer chan opencdc.Record
+
+	// stateStore persists the row states of the readers, nil if the row states aren't needed
+	stateStore *state.Store
+	// state is the last saved state, the rows of the readers' sheets are stored under their state.SheetKey
+	state *state.State
+	// pending are the row states taken after each poll, saved once all the records of their poll are acked
+	pending   []pendingState
+	pendingMu sync.Mutex
+}
+
+// spreadsheetReader is the reader of the sheets(tabs) of a spreadsheet, along with their row offsets
+type spreadsheetReader struct {
+	// sheetsReader is the instance of MultiReader, which is a wrapper calling BatchGet Google sheets API
+	// for all the sheets(tabs) read
+	sheetsReader  *sheets.MultiReader
+	spreadsheetID string
+	// allSheets enables the discovery of the sheets of the spreadsheet
+	allSheets bool
+	// offsets are the row numbers of the last fetched rows, by gid
+	offsets map[int64]int64
+}
+
+// sheetsDiscovery is the discovered sheets(tabs) of the spreadsheet of the reader
+type sheetsDiscovery struct {
+	reader    *spreadsheetReader
+	discovery *sheets.SheetsDiscovery
+}
+
+// driveFilesChange is the readers of the spreadsheets of the Drive folder, after spreadsheets were added or removed
+type driveFilesChange struct {
+	readers []*spreadsheetReader
+	removed []*spreadsheetReader
+}
+
+// pendingState is the row states of the readers taken after a poll, waiting for its records to be acked
+type pendingState struct {
+	// records is the number of records of the poll not acked yet
+	records int
+	// rows are the row states by state.SheetKey
+	rows map[string]map[int64]state.Row
 }
 
 // NewSheetsIterator creates a new instance of sheets iterator and starts polling google sheets api for new changes
-// using the row offset of last successful row read in a separate go routine, row offset is received in sheet position
+// using the row offsets of last successful rows read in a separate go routine, row offsets are received in sheet position
+// The args hold the reader args of each spreadsheet, the polling periods of the first args being used.
+// The row states of the readers are loaded from, and saved on ack to, the stateStore, unless it's nil.
 func NewSheetsIterator(ctx context.Context,
 	tp position.SheetPosition,
-	args sheets.BatchReaderArgs,
+	args []sheets.MultiReaderArgs,
+	stateStore *state.Store,
 ) (*SheetsIterator, error) {
-	tmbWithCtx, _ := tomb.WithContext(ctx)
-	sheetsReader, err := sheets.NewBatchReader(ctx, args)
+	if len(args) == 0 {
+		return nil, errors.New("no spreadsheet to read")
+	}
+
+	cdc, err := newSheetsIterator(ctx, args[0], stateStore)
 	if err != nil {
-		return nil, fmt.Errorf("error initializing sheets BatchReader: %w", err)
+		return nil, err
 	}
+	for _, readerArgs := range args {
+		reader, err := cdc.newSpreadsheetReader(ctx, readerArgs)
+		if err != nil {
+			return nil, err
+		}
+		cdc.readers = append(cdc.readers, reader)
+	}
+	cdc.start(ctx, tp, args[0].SheetsRefreshPeriod)
+
+	return cdc, nil
+}
 
-	cdc := &SheetsIterator{
-		sheetsReader: sheetsReader,
-		rowOffset:    tp.RowOffset,
-		tomb:         tmbWithCtx,
-		ticker:       time.NewTicker(args.PollingPeriod),
+// NewDriveFolderIterator creates a new instance of sheets iterator reading the spreadsheets of the Drive folder,
+// each spreadsheet being read with a copy of the args, the spreadsheets added to the folder are read
+// as they're found, every SheetsRefreshPeriod. The spreadsheets which can't be read, e.g. missing the configured sheet,
+// are skipped with a warning and tried again on the next refresh.
+func NewDriveFolderIterator(ctx context.Context,
+	tp position.SheetPosition,
+	folder *sheets.DriveFolder,
+	args sheets.MultiReaderArgs,
+	stateStore *state.Store,
+) (*SheetsIterator, error) {
+	files, err := folder.Spreadsheets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cdc, err := newSheetsIterator(ctx, args, stateStore)
+	if err != nil {
+		return nil, err
+	}
+	cdc.folder = folder
+	cdc.folderArgs = args
+	for _, file := range files {
+		reader, err := cdc.newSpreadsheetReader(ctx, driveFileArgs(args, file))
+		if err != nil {
+			sdk.Logger(ctx).Warn().Err(err).Str("file_id", file.ID).Msg("unable to read the spreadsheet, skipping it")
+			continue
+		}
+		cdc.readers = append(cdc.readers, reader)
+	}
+	cdc.start(ctx, tp, args.SheetsRefreshPeriod)
+
+	return cdc, nil
+}
+
+// newSheetsIterator creates the sheets iterator without readers, loading the row states from the stateStore
+func newSheetsIterator(ctx context.Context, args sheets.MultiReaderArgs, stateStore *state.Store) (*SheetsIterator, error) {
+	tmbWithCtx, _ := tomb.WithContext(ctx)
+
+	var sheetsState *state.State
+	if stateStore != nil {
+		var err error
+		sheetsState, err = stateStore.Load()
+		if err != nil {
+			return nil, fmt.Errorf("error loading state: %w", err)
+		}
+	}
+
+	return &SheetsIterator{
+		tomb:   tmbWithCtx,
+		ticker: time.NewTicker(args.PollingPeriod),
 		// keeping the length as 1 to be able to have 2nd cache of records ready when the first batch of records are successfully read
 		caches: make(chan []opencdc.Record, 1),
 		// keeping the buffer size as one, to enable checking the availability of records using len() function on channel
-		buffer: make(chan opencdc.Record, 1),
+		buffer:      make(chan opencdc.Record, 1),
+		discoveries: make(chan sheetsDiscovery, 1),
+		files:       make(chan driveFilesChange, 1),
+
+		snapshotArgs: args.Snapshot,
+
+		stateStore: stateStore,
+		state:      sheetsState,
+	}, nil
+}
+
+// newSpreadsheetReader creates the reader of the spreadsheet, with the row states of its sheets from the state
+func (c *SheetsIterator) newSpreadsheetReader(ctx context.Context, args sheets.MultiReaderArgs) (*spreadsheetReader, error) {
+	sheetsReader, err := sheets.NewMultiReader(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing sheets MultiReader: %w", err)
+	}
+	if c.state != nil {
+		rows := make(map[int64]map[int64]state.Row)
+		// the state is updated by the acks once the iterator is started
+		c.pendingMu.Lock()
+		for _, sheetID := range sheetsReader.SheetIDs() {
+			rows[sheetID] = c.state.Sheets[state.SheetKey(args.SpreadsheetID, sheetID)]
+		}
+		c.pendingMu.Unlock()
+		sheetsReader.SetRowStates(rows)
 	}
+	return &spreadsheetReader{
+		sheetsReader:  sheetsReader,
+		spreadsheetID: args.SpreadsheetID,
+		allSheets:     args.AllSheets,
+	}, nil
+}
 
-	cdc.tomb.Go(cdc.startIterator(ctx))
-	cdc.tomb.Go(cdc.flush)
+// start sets the row offsets of the readers from the position and starts the go routines
+func (c *SheetsIterator) start(ctx context.Context, tp position.SheetPosition, refreshPeriod time.Duration) {
+	for _, reader := range c.readers {
+		reader.offsets = spreadsheetOffsets(tp, reader.spreadsheetID, reader.sheetsReader.SheetIDs(), len(c.readers))
+	}
 
-	return cdc, nil
+	c.tomb.Go(c.startIterator(ctx))
+	c.tomb.Go(c.flush)
+	if c.folder != nil || slices.ContainsFunc(c.readers, func(reader *spreadsheetReader) bool { return reader.allSheets }) {
+		// the offsets of the spreadsheets skipped at start are used once they're read
+		var skipped map[string]map[int64]int64
+		if c.folder != nil {
+			skipped = maps.Clone(tp.Spreadsheets)
+			for _, reader := range c.readers {
+				delete(skipped, reader.spreadsheetID)
+			}
+		}
+		c.tomb.Go(c.refreshSheets(ctx, refreshPeriod, slices.Clone(c.readers), skipped))
+	}
+}
+
+// driveFileArgs returns the reader args of the spreadsheet of the Drive folder
+func driveFileArgs(args sheets.MultiReaderArgs, file sheets.DriveFile) sheets.MultiReaderArgs {
+	args.SpreadsheetID = file.ID
+	args.DriveFile = &file
+	return args
+}
+
+// spreadsheetOffsets returns the row offsets of the sheets of the spreadsheet by gid, from the position.
+// A position without the offsets of all the spreadsheets holds the offsets of a single spreadsheet,
+// the only spreadsheet read, or the spreadsheet with its ID.
+func spreadsheetOffsets(tp position.SheetPosition, spreadsheetID string, sheetIDs []int64, spreadsheets int) map[int64]int64 {
+	switch {
+	case tp.Spreadsheets != nil:
+		return sheetOffsets(position.SheetPosition{SheetOffsets: tp.Spreadsheets[spreadsheetID]}, sheetIDs)
+	case spreadsheets == 1 || tp.SpreadsheetID == spreadsheetID:
+		return sheetOffsets(tp, sheetIDs)
+	default:
+		return make(map[int64]int64)
+	}
+}
+
+// sheetOffsets returns the row offsets of the sheets by gid, from the position.
+// A position without the offsets of all the sheets holds the offset of a single sheet, the only sheet read,
+// or the sheet with its gid. The offsets of the sheets not read anymore are dropped.
+func sheetOffsets(tp position.SheetPosition, sheetIDs []int64) map[int64]int64 {
+	offsets := make(map[int64]int64, len(sheetIDs))
+	if tp.SheetOffsets != nil {
+		// the sheets not read anymore are dropped from the position
+		for _, sheetID := range sheetIDs {
+			if offset, ok := tp.SheetOffsets[sheetID]; ok {
+				offsets[sheetID] = offset
+			}
+		}
+		return offsets
+	}
+	switch {
+	case tp.RowOffset == 0:
+	case len(sheetIDs) == 1:
+		offsets[sheetIDs[0]] = tp.RowOffset
+	default:
+		offsets[tp.SheetID] = tp.RowOffset
+	}
+	return offsets
 }
 
 // startIterator is the go routine function used to poll the google sheets API for new changes at regular intervals
 func (c *SheetsIterator) startIterator(ctx context.Context) func() error {
 	return func() error {
 		defer close(c.caches)
+		if err := c.snapshot(ctx); err != nil {
+			return err
+		}
+		// ready is always ready to receive, nextPage is set to ready when the next page of the spreadsheet
+		// polled last is fetched right away, without waiting for the next tick
+		ready := make(chan time.Time)
+		close(ready)
+		var nextPage <-chan time.Time
 		for {
 			select {
 			case <-c.tomb.Dying():
 				return c.tomb.Err()
+			case discovery := <-c.discoveries:
+				if err := c.setSheets(discovery.reader, discovery.discovery); err != nil {
+					return err
+				}
+			case change := <-c.files:
+				c.setReaders(change)
+				nextPage = nil
 			case <-c.ticker.C:
-				records, err := c.sheetsReader.GetSheetRecords(ctx, c.rowOffset)
+				if len(c.readers) == 0 {
+					// the Drive folder has no spreadsheet yet
+					continue
+				}
+				// a single spreadsheet is polled per tick, along with its next pages, keeping the API requests rate
+				// of a single spreadsheet
+				more, err := c.poll(ctx, c.readers[c.next])
+				if err != nil {
+					return err
+				}
+				nextPage = c.nextPage(more, ready)
+			case <-nextPage:
+				more, err := c.poll(ctx, c.readers[c.next])
 				if err != nil {
-					return fmt.Errorf("unable to fetch records: %w", err)
+					return err
 				}
-				if len(records) == 0 {
+				nextPage = c.nextPage(more, ready)
+			}
+		}
+	}
+}
+
+// nextPage returns the ready channel if the next page of the spreadsheet polled last is fetched right away,
+// moving on to the next spreadsheet and returning nil otherwise
+func (c *SheetsIterator) nextPage(more bool, ready <-chan time.Time) <-chan time.Time {
+	if more {
+		return ready
+	}
+	c.next = (c.next + 1) % len(c.readers)
+	return nil
+}
+
+// poll fetches the records of the spreadsheet of the reader, and sends them to the caches,
+// reporting whether a page of rows was truncated, the next page being fetched right away
+func (c *SheetsIterator) poll(ctx context.Context, reader *spreadsheetReader) (bool, error) {
+	records, err := reader.sheetsReader.GetSheetRecords(ctx, reader.offsets)
+	if err != nil {
+		return false, fmt.Errorf("unable to fetch records: %w", err)
+	}
+	more := reader.sheetsReader.MorePages()
+	if len(records) == 0 {
+		return more, nil
+	}
+	if err := c.send(reader, records); err != nil {
+		return false, err
+	}
+	return more, nil
+}
+
+// snapshot reads the rows of the spreadsheets read at start, in parallel pages, sending them to the caches in row order
+func (c *SheetsIterator) snapshot(ctx context.Context) error {
+	// the page requests are canceled when the iterator is stopped
+	ctx = c.tomb.Context(ctx)
+	for _, reader := range c.readers {
+		err := reader.sheetsReader.Snapshot(ctx, reader.offsets, c.snapshotArgs, func(records []opencdc.Record) error {
+			return c.send(reader, records)
+		})
+		select {
+		case <-c.tomb.Dying():
+			return c.tomb.Err()
+		default:
+		}
+		if err != nil {
+			return fmt.Errorf("unable to snapshot spreadsheet(%s): %w", reader.spreadsheetID, err)
+		}
+	}
+	return nil
+}
+
+// send sends the records of the spreadsheet of the reader to the caches, moving the row offsets of the reader
+func (c *SheetsIterator) send(reader *spreadsheetReader, records []opencdc.Record) error {
+	var err error
+	// the positions of the Drive folder spreadsheets are always by spreadsheet, spreadsheets being added later on
+	if len(c.readers) > 1 || c.folder != nil {
+		if records, err = c.spreadsheetsPositions(reader, records); err != nil {
+			return err
+		}
+	}
+
+	if c.stateStore != nil {
+		rows := make(map[string]map[int64]state.Row)
+		for sheetID, sheetRows := range reader.sheetsReader.RowStates() {
+			rows[state.SheetKey(reader.spreadsheetID, sheetID)] = sheetRows
+		}
+		// queued before the records are sent, so the acks of the records can't come first
+		c.pendingMu.Lock()
+		c.pending = append(c.pending, pendingState{records: len(records), rows: rows})
+		c.pendingMu.Unlock()
+	}
+	select {
+	case c.caches <- records:
+		pos, err := position.ParseRecordPosition(records[len(records)-1].Position)
+		if err != nil {
+			return fmt.Errorf("failed to parse record position: %w", err)
+		}
+		reader.offsets = pos.SheetOffsets
+		return nil
+	case <-c.tomb.Dying():
+		return c.tomb.Err()
+	}
+}
+
+// spreadsheetsPositions adds the row offsets of the sheets of all the spreadsheets, by spreadsheet ID,
+// to the positions of the records of the reader's spreadsheet
+func (c *SheetsIterator) spreadsheetsPositions(reader *spreadsheetReader, records []opencdc.Record) ([]opencdc.Record, error) {
+	offsets := make(map[string]map[int64]int64, len(c.readers))
+	for _, r := range c.readers {
+		offsets[r.spreadsheetID] = r.offsets
+	}
+	for i, record := range records {
+		pos, err := position.ParseRecordPosition(record.Position)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse record position: %w", err)
+		}
+		// the sheet offsets are never modified, only replaced, the maps can be shared between the positions
+		offsets[reader.spreadsheetID] = pos.SheetOffsets
+		pos.Spreadsheets = maps.Clone(offsets)
+		records[i].Position = pos.RecordPosition()
+	}
+	return records, nil
+}
+
+// refreshSheets is the go routine function used to discover the sheets(tabs) of the spreadsheets, and the spreadsheets
+// of the Drive folder, at regular intervals, the discoveries being applied by startIterator, so the sheets don't change
+// during a poll. The readers are the readers of the spreadsheets as of the start, skipped are the row offsets
+// of the spreadsheets of the folder which weren't read at start.
+func (c *SheetsIterator) refreshSheets(
+	ctx context.Context,
+	period time.Duration,
+	readers []*spreadsheetReader,
+	skipped map[string]map[int64]int64,
+) func() error {
+	return func() error {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.tomb.Dying():
+				return c.tomb.Err()
+			case <-ticker.C:
+			}
+			if c.folder != nil {
+				change, ok := c.refreshFiles(ctx, readers, skipped)
+				if ok {
+					select {
+					case c.files <- change:
+						readers = change.readers
+					case <-c.tomb.Dying():
+						return c.tomb.Err()
+					}
+				}
+			}
+			for _, reader := range readers {
+				if !reader.allSheets {
+					continue
+				}
+				discovery, err := reader.sheetsReader.DiscoverSheets(ctx)
+				if err != nil {
+					// the sheets are discovered again on the next tick, the known sheets are read meanwhile
+					sdk.Logger(ctx).Warn().Err(err).Str("spreadsheet_id", reader.spreadsheetID).Msg("unable to discover the sheets")
 					continue
 				}
 				select {
-				case c.caches <- records:
-					pos, err := position.ParseRecordPosition(records[len(records)-1].Position)
-					if err != nil {
-						return fmt.Errorf("failed to parse record position: %w", err)
-					}
-					c.rowOffset = pos.RowOffset
+				case c.discoveries <- sheetsDiscovery{reader: reader, discovery: discovery}:
 				case <-c.tomb.Dying():
 					return c.tomb.Err()
 				}
@@ -103,6 +474,105 @@ func (c *SheetsIterator) startIterator(ctx context.Context) func() error {
 	}
 }
 
+// refreshFiles lists the spreadsheets of the Drive folder, creating the readers of the added spreadsheets,
+// it returns false if the spreadsheets of the readers didn't change
+func (c *SheetsIterator) refreshFiles(
+	ctx context.Context,
+	readers []*spreadsheetReader,
+	skipped map[string]map[int64]int64,
+) (driveFilesChange, bool) {
+	files, err := c.folder.Spreadsheets(ctx)
+	if err != nil {
+		// the folder is listed again on the next tick, the known spreadsheets are read meanwhile
+		sdk.Logger(ctx).Warn().Err(err).Str("folder_id", c.folder.FolderID()).Msg("unable to list the spreadsheets")
+		return driveFilesChange{}, false
+	}
+
+	known := make(map[string]*spreadsheetReader, len(readers))
+	for _, reader := range readers {
+		known[reader.spreadsheetID] = reader
+	}
+
+	// a new slice, the readers slice is shared with startIterator
+	var change driveFilesChange
+	changed := false
+	for _, file := range files {
+		if reader, ok := known[file.ID]; ok {
+			change.readers = append(change.readers, reader)
+			delete(known, file.ID)
+			continue
+		}
+		reader, err := c.newSpreadsheetReader(ctx, driveFileArgs(c.folderArgs, file))
+		if err != nil {
+			sdk.Logger(ctx).Warn().Err(err).Str("file_id", file.ID).Msg("unable to read the spreadsheet, skipping it")
+			continue
+		}
+		reader.offsets = sheetOffsets(position.SheetPosition{SheetOffsets: skipped[file.ID]}, reader.sheetsReader.SheetIDs())
+		delete(skipped, file.ID)
+		change.readers = append(change.readers, reader)
+		changed = true
+	}
+	for _, reader := range readers {
+		if _, ok := known[reader.spreadsheetID]; ok {
+			change.removed = append(change.removed, reader)
+			changed = true
+		}
+	}
+	return change, changed
+}
+
+// setReaders applies the readers of the spreadsheets of the Drive folder, dropping the removed spreadsheets
+// from the offsets and the state
+func (c *SheetsIterator) setReaders(change driveFilesChange) {
+	c.readers = change.readers
+	if len(c.readers) > 0 {
+		c.next %= len(c.readers)
+	} else {
+		c.next = 0
+	}
+	for _, reader := range change.removed {
+		c.dropState(reader.spreadsheetID, reader.sheetsReader.SheetIDs())
+	}
+}
+
+// setSheets applies the discovered sheets of the reader, dropping the removed sheets from the offsets and the state
+func (c *SheetsIterator) setSheets(reader *spreadsheetReader, discovery *sheets.SheetsDiscovery) error {
+	removed, err := reader.sheetsReader.SetSheets(discovery)
+	if err != nil {
+		return fmt.Errorf("unable to set the discovered sheets: %w", err)
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	// offsets is only used by the startIterator go routine, the next records positions won't hold the removed sheets
+	offsets := maps.Clone(reader.offsets)
+	for _, sheetID := range removed {
+		delete(offsets, sheetID)
+	}
+	reader.offsets = offsets
+
+	c.dropState(reader.spreadsheetID, removed)
+	return nil
+}
+
+// dropState drops the row states of the sheets of the spreadsheet not read anymore
+func (c *SheetsIterator) dropState(spreadsheetID string, sheetIDs []int64) {
+	if c.stateStore == nil {
+		return
+	}
+	// the state is saved on the next ack, the pending row states of the removed sheets mustn't be saved back
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	for _, sheetID := range sheetIDs {
+		key := state.SheetKey(spreadsheetID, sheetID)
+		delete(c.state.Sheets, key)
+		for _, pending := range c.pending {
+			delete(pending.rows, key)
+		}
+	}
+}
+
 // flush is the go routine, responsible for getting the array of records in caches channel
 // and pushing them into read buffer to be returned by Next function
 func (c *SheetsIterator) flush() error {
@@ -143,6 +613,33 @@ func (c *SheetsIterator) Next(ctx context.Context) (opencdc.Record, error) {
 	}
 }
 
+// Ack saves the row states of the poll once all of its records are acked, acks being received in the order of the records
+func (c *SheetsIterator) Ack(_ context.Context) error {
+	if c.stateStore == nil {
+		return nil
+	}
+
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	if len(c.pending) == 0 {
+		return nil
+	}
+
+	c.pending[0].records--
+	if c.pending[0].records > 0 {
+		return nil
+	}
+
+	for key, rows := range c.pending[0].rows {
+		c.state.Sheets[key] = rows
+	}
+	c.pending = c.pending[1:]
+	if err := c.stateStore.Save(c.state); err != nil {
+		return fmt.Errorf("error saving state: %w", err)
+	}
+	return nil
+}
+
 // Stop the go routines and ticker
 func (c *SheetsIterator) Stop(ctx context.Context) {
 	sdk.Logger(ctx).Trace().Msg("iterator stopped")
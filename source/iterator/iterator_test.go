@@ -16,45 +16,86 @@ package iterator
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/conduitio-labs/conduit-connector-google-sheets/sheets"
 	"github.com/conduitio-labs/conduit-connector-google-sheets/source/position"
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/state"
 	"github.com/conduitio/conduit-commons/opencdc"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+	sheetsapi "google.golang.org/api/sheets/v4"
 	"gopkg.in/tomb.v2"
 )
 
 func TestNewSheetsIterator(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"properties":{"title":"Dummy"},"sheets":[{"properties":{"sheetId":0,"title":"Sheet1"}}]}`))
+	}))
+	defer testServer.Close()
+	clientArgs := sheets.ClientArgs{
+		TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+		Endpoint:    testServer.URL,
+	}
+
 	tests := []struct {
 		name string
-		args sheets.BatchReaderArgs
+		args []sheets.MultiReaderArgs
 		tp   position.SheetPosition
 		err  error
 	}{
 		{
 			name: "NewSheetsIterator with RowOffset=0",
-			args: sheets.BatchReaderArgs{
+			args: []sheets.MultiReaderArgs{{BatchReaderArgs: sheets.BatchReaderArgs{
+				ClientArgs:    clientArgs,
 				SpreadsheetID: "SPREADSHEET_ID",
 				PollingPeriod: time.Millisecond,
-			},
+			}}},
 			tp: position.SheetPosition{RowOffset: 0},
 		}, {
 			name: "NewSheetsIterator without SheetID",
-			args: sheets.BatchReaderArgs{
+			args: []sheets.MultiReaderArgs{{BatchReaderArgs: sheets.BatchReaderArgs{
+				ClientArgs:    clientArgs,
 				SpreadsheetID: "SPREADSHEET_ID",
 				PollingPeriod: time.Millisecond,
-			},
+			}}},
 			tp: position.SheetPosition{
 				RowOffset: 5,
 			},
+		}, {
+			name: "NewSheetsIterator with several spreadsheets",
+			args: []sheets.MultiReaderArgs{{BatchReaderArgs: sheets.BatchReaderArgs{
+				ClientArgs:    clientArgs,
+				SpreadsheetID: "SPREADSHEET_ID",
+				PollingPeriod: time.Millisecond,
+			}}, {BatchReaderArgs: sheets.BatchReaderArgs{
+				ClientArgs:    clientArgs,
+				SpreadsheetID: "OTHER_SPREADSHEET_ID",
+				PollingPeriod: time.Millisecond,
+			}}},
+			tp: position.SheetPosition{
+				RowOffset:     5,
+				SpreadsheetID: "OTHER_SPREADSHEET_ID",
+			},
+		}, {
+			name: "NewSheetsIterator without spreadsheet",
+			err:  errors.New("no spreadsheet to read"),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			res, err := NewSheetsIterator(context.Background(), tt.tp, tt.args)
+			res, err := NewSheetsIterator(context.Background(), tt.tp, tt.args, nil)
 			if tt.err != nil {
 				assert.EqualError(t, err, tt.err.Error())
 			} else {
@@ -156,3 +197,315 @@ func TestNext(t *testing.T) {
 	assert.EqualError(t, err, ctx.Err().Error())
 	assert.Empty(t, out)
 }
+
+func TestAck_SavesStateOncePollIsAcked(t *testing.T) {
+	store := state.NewStore(filepath.Join(t.TempDir(), "state.json"))
+	rows := map[int64]state.Row{1: state.NewRow([]byte(`["a"]`))}
+	cdc := &SheetsIterator{
+		stateStore: store,
+		state:      &state.State{Sheets: map[string]map[int64]state.Row{}},
+		pending: []pendingState{{records: 2, rows: map[string]map[int64]state.Row{
+			state.SheetKey("dummy_spreadsheet", 1234): rows,
+		}}},
+	}
+
+	assert.NoError(t, cdc.Ack(context.Background()))
+	got, err := store.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, got.Sheets)
+
+	assert.NoError(t, cdc.Ack(context.Background()))
+	got, err = store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, rows, got.Sheets[state.SheetKey("dummy_spreadsheet", 1234)])
+	assert.Empty(t, cdc.pending)
+}
+
+func TestSheetOffsets(t *testing.T) {
+	tests := []struct {
+		name     string
+		tp       position.SheetPosition
+		sheetIDs []int64
+		want     map[int64]int64
+	}{{
+		name:     "no position",
+		sheetIDs: []int64{0, 1234},
+		want:     map[int64]int64{},
+	}, {
+		name:     "single sheet position",
+		tp:       position.SheetPosition{RowOffset: 5, SheetID: 0},
+		sheetIDs: []int64{1234},
+		want:     map[int64]int64{1234: 5},
+	}, {
+		name:     "single sheet position with several sheets",
+		tp:       position.SheetPosition{RowOffset: 5, SheetID: 1234},
+		sheetIDs: []int64{0, 1234},
+		want:     map[int64]int64{1234: 5},
+	}, {
+		name:     "multi sheets position",
+		tp:       position.SheetPosition{RowOffset: 5, SheetID: 1234, SheetOffsets: map[int64]int64{0: 3, 1234: 5}},
+		sheetIDs: []int64{0, 1234},
+		want:     map[int64]int64{0: 3, 1234: 5},
+	}, {
+		name:     "multi sheets position with a removed sheet",
+		tp:       position.SheetPosition{RowOffset: 5, SheetID: 1234, SheetOffsets: map[int64]int64{0: 3, 1234: 5}},
+		sheetIDs: []int64{0, 42},
+		want:     map[int64]int64{0: 3},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sheetOffsets(tt.tp, tt.sheetIDs))
+		})
+	}
+}
+
+func TestSetSheets_DropsRemovedSheets(t *testing.T) {
+	var sheetsJSON atomic.Value
+	sheetsJSON.Store(`[{"properties":{"sheetId":0,"title":"Orders"}},{"properties":{"sheetId":1234,"title":"Returns"}}]`)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"properties":{"title":"Dummy"},"sheets":` + sheetsJSON.Load().(string) + `}`))
+	}))
+	defer testServer.Close()
+
+	reader, err := sheets.NewMultiReader(context.Background(), sheets.MultiReaderArgs{
+		BatchReaderArgs: sheets.BatchReaderArgs{
+			ClientArgs: sheets.ClientArgs{
+				TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+				Endpoint:    testServer.URL,
+			},
+			SpreadsheetID: "dummy_spreadsheet",
+		},
+		AllSheets: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 1234}, reader.SheetIDs())
+
+	rows := map[int64]state.Row{1: state.NewRow([]byte(`["a"]`))}
+	cdc := &SheetsIterator{
+		readers: []*spreadsheetReader{{
+			sheetsReader:  reader,
+			spreadsheetID: "dummy_spreadsheet",
+			allSheets:     true,
+			offsets:       map[int64]int64{0: 3, 1234: 5},
+		}},
+		stateStore: state.NewStore(filepath.Join(t.TempDir(), "state.json")),
+		state: &state.State{Sheets: map[string]map[int64]state.Row{
+			state.SheetKey("dummy_spreadsheet", 0):    rows,
+			state.SheetKey("dummy_spreadsheet", 1234): rows,
+		}},
+		pending: []pendingState{{records: 1, rows: map[string]map[int64]state.Row{
+			state.SheetKey("dummy_spreadsheet", 0):    rows,
+			state.SheetKey("dummy_spreadsheet", 1234): rows,
+		}}},
+	}
+
+	// the Returns sheet is removed, and the Refunds sheet added
+	sheetsJSON.Store(`[{"properties":{"sheetId":0,"title":"Orders"}},{"properties":{"sheetId":42,"title":"Refunds"}}]`)
+	discovery, err := reader.DiscoverSheets(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, cdc.setSheets(cdc.readers[0], discovery))
+
+	assert.Equal(t, []int64{0, 42}, reader.SheetIDs())
+	assert.Equal(t, map[int64]int64{0: 3}, cdc.readers[0].offsets)
+	assert.Equal(t, map[string]map[int64]state.Row{state.SheetKey("dummy_spreadsheet", 0): rows}, cdc.state.Sheets)
+	assert.Equal(t, map[string]map[int64]state.Row{state.SheetKey("dummy_spreadsheet", 0): rows}, cdc.pending[0].rows)
+}
+
+func TestRefreshFiles(t *testing.T) {
+	var filesJSON atomic.Value
+	filesJSON.Store(`[{"id":"file1","name":"2024-01 Expenses"}]`)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/files":
+			_, _ = w.Write([]byte(`{"files":` + filesJSON.Load().(string) + `}`))
+		case "/v4/spreadsheets/broken":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found."}}`))
+		default:
+			_, _ = w.Write([]byte(`{"properties":{"title":"Dummy"},"sheets":[{"properties":{"sheetId":0,"title":"Sheet1"}}]}`))
+		}
+	}))
+	defer testServer.Close()
+	clientArgs := sheets.ClientArgs{
+		TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+		Endpoint:    testServer.URL,
+	}
+
+	folder, err := sheets.NewDriveFolder(context.Background(), sheets.DriveFolderArgs{ClientArgs: clientArgs, FolderID: "folder1"})
+	assert.NoError(t, err)
+	args := sheets.MultiReaderArgs{
+		BatchReaderArgs: sheets.BatchReaderArgs{
+			ClientArgs:    clientArgs,
+			PollingPeriod: time.Hour,
+		},
+		SheetsRefreshPeriod: time.Hour,
+	}
+	cdc, err := NewDriveFolderIterator(context.Background(), position.SheetPosition{}, folder, args, nil)
+	assert.NoError(t, err)
+	// the files are refreshed by the test
+	cdc.Stop(context.Background())
+	_ = cdc.tomb.Wait()
+	assert.Len(t, cdc.readers, 1)
+	cdc.readers[0].offsets = map[int64]int64{0: 3}
+	rows := map[int64]state.Row{1: state.NewRow([]byte(`["a"]`))}
+	cdc.stateStore = state.NewStore(filepath.Join(t.TempDir(), "state.json"))
+	cdc.state = &state.State{Sheets: map[string]map[int64]state.Row{state.SheetKey("file1", 0): rows}}
+
+	// the change isn't applied when the spreadsheets of the folder didn't change
+	_, ok := cdc.refreshFiles(context.Background(), cdc.readers, nil)
+	assert.False(t, ok)
+
+	// file1 is removed, file2 added with the offsets of the position, broken skipped
+	filesJSON.Store(`[{"id":"file2","name":"2024-02 Expenses"},{"id":"broken","name":"Draft"}]`)
+	change, ok := cdc.refreshFiles(context.Background(), cdc.readers, map[string]map[int64]int64{"file2": {0: 7}})
+	assert.True(t, ok)
+	cdc.setReaders(change)
+
+	assert.Len(t, cdc.readers, 1)
+	assert.Equal(t, "file2", cdc.readers[0].spreadsheetID)
+	assert.Equal(t, map[int64]int64{0: 7}, cdc.readers[0].offsets)
+	assert.Equal(t, 0, cdc.next)
+	assert.Empty(t, cdc.state.Sheets)
+}
+
+func TestSpreadsheetOffsets(t *testing.T) {
+	tests := []struct {
+		name         string
+		tp           position.SheetPosition
+		spreadsheets int
+		want         map[int64]int64
+	}{{
+		name:         "single spreadsheet position",
+		tp:           position.SheetPosition{RowOffset: 5, SpreadsheetID: "other", SheetID: 0},
+		spreadsheets: 1,
+		want:         map[int64]int64{0: 5},
+	}, {
+		name:         "single spreadsheet position with several spreadsheets",
+		tp:           position.SheetPosition{RowOffset: 5, SpreadsheetID: "dummy_spreadsheet", SheetID: 0},
+		spreadsheets: 2,
+		want:         map[int64]int64{0: 5},
+	}, {
+		name:         "position of another spreadsheet",
+		tp:           position.SheetPosition{RowOffset: 5, SpreadsheetID: "other", SheetID: 0},
+		spreadsheets: 2,
+		want:         map[int64]int64{},
+	}, {
+		name: "multi spreadsheets position",
+		tp: position.SheetPosition{RowOffset: 5, SpreadsheetID: "other", SheetID: 0, Spreadsheets: map[string]map[int64]int64{
+			"dummy_spreadsheet": {0: 3},
+			"other":             {0: 5},
+		}},
+		spreadsheets: 2,
+		want:         map[int64]int64{0: 3},
+	}, {
+		name: "multi spreadsheets position without the spreadsheet",
+		tp: position.SheetPosition{RowOffset: 5, SpreadsheetID: "other", SheetID: 0, Spreadsheets: map[string]map[int64]int64{
+			"other": {0: 5},
+		}},
+		spreadsheets: 2,
+		want:         map[int64]int64{},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, spreadsheetOffsets(tt.tp, "dummy_spreadsheet", []int64{0}, tt.spreadsheets))
+		})
+	}
+}
+
+func TestSpreadsheetsPositions(t *testing.T) {
+	first := &spreadsheetReader{spreadsheetID: "first", offsets: map[int64]int64{0: 3}}
+	second := &spreadsheetReader{spreadsheetID: "second", offsets: map[int64]int64{0: 7}}
+	cdc := &SheetsIterator{readers: []*spreadsheetReader{first, second}}
+
+	records := []opencdc.Record{
+		{Position: position.SheetPosition{RowOffset: 8, SpreadsheetID: "second", SheetOffsets: map[int64]int64{0: 8}}.RecordPosition()},
+		{Position: position.SheetPosition{RowOffset: 9, SpreadsheetID: "second", SheetOffsets: map[int64]int64{0: 9}}.RecordPosition()},
+	}
+	records, err := cdc.spreadsheetsPositions(second, records)
+	assert.NoError(t, err)
+
+	for i, want := range []map[string]map[int64]int64{
+		{"first": {0: 3}, "second": {0: 8}},
+		{"first": {0: 3}, "second": {0: 9}},
+	} {
+		pos, err := position.ParseRecordPosition(records[i].Position)
+		assert.NoError(t, err)
+		assert.Equal(t, want, pos.Spreadsheets)
+	}
+	// the offsets of the reader are only updated once the records are sent
+	assert.Equal(t, map[int64]int64{0: 7}, second.offsets)
+}
+
+func TestStartIterator_NextPages(t *testing.T) {
+	// the sheet of the big spreadsheet holds 6 rows read in pages of 2 rows, the small spreadsheet a single row
+	var (
+		requests   []string
+		requestsMu sync.Mutex
+	)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spreadsheetID := strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/")
+		spreadsheetID, ok := strings.CutSuffix(spreadsheetID, "/values:batchGetByDataFilter")
+		if !ok {
+			_, _ = w.Write([]byte(`{"properties":{"title":"Dummy"},"sheets":[
+				{"properties":{"sheetId":0,"title":"Sheet1","gridProperties":{"rowCount":6}}}
+			]}`))
+			return
+		}
+		requestsMu.Lock()
+		requests = append(requests, spreadsheetID)
+		requestsMu.Unlock()
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var request sheetsapi.BatchGetValuesByDataFilterRequest
+		assert.NoError(t, json.Unmarshal(body, &request))
+		rows := request.DataFilters[0].GridRange
+		last := min(rows.EndRowIndex, 6)
+		if spreadsheetID == "small" {
+			last = 1
+		}
+		values := make([][]any, 0)
+		for row := rows.StartRowIndex; row < last; row++ {
+			values = append(values, []any{fmt.Sprintf("%s%d", spreadsheetID, row+1)})
+		}
+		_ = json.NewEncoder(w).Encode(sheetsapi.BatchGetValuesByDataFilterResponse{
+			ValueRanges: []*sheetsapi.MatchedValueRange{{ValueRange: &sheetsapi.ValueRange{Values: values}}},
+		})
+	}))
+	defer testServer.Close()
+	clientArgs := sheets.ClientArgs{
+		TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "dummy"}),
+		Endpoint:    testServer.URL,
+	}
+	args := make([]sheets.MultiReaderArgs, 0, 2)
+	for _, spreadsheetID := range []string{"big", "small"} {
+		args = append(args, sheets.MultiReaderArgs{BatchReaderArgs: sheets.BatchReaderArgs{
+			ClientArgs:    clientArgs,
+			SpreadsheetID: spreadsheetID,
+			PollingPeriod: 100 * time.Millisecond,
+			BatchSize:     2,
+		}})
+	}
+
+	cdc, err := NewSheetsIterator(context.Background(), position.SheetPosition{}, args, nil)
+	assert.NoError(t, err)
+	defer cdc.Stop(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var payloads []string
+	for range 7 {
+		record, err := cdc.Next(ctx)
+		assert.NoError(t, err)
+		payloads = append(payloads, string(record.Payload.After.Bytes()))
+	}
+	// the pages of the big spreadsheet are read right away, before the small spreadsheet is polled on the next tick
+	assert.Equal(t, []string{
+		`["big1"]`, `["big2"]`, `["big3"]`, `["big4"]`, `["big5"]`, `["big6"]`, `["small1"]`,
+	}, payloads)
+
+	requestsMu.Lock()
+	defer requestsMu.Unlock()
+	// the empty page at the end of the grid ends the paging
+	assert.Equal(t, []string{"big", "big", "big", "big", "small"}, requests[:5])
+}
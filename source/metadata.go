@@ -0,0 +1,41 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import "github.com/conduitio-labs/conduit-connector-google-sheets/sheets"
+
+// Metadata keys set on the records read by the source, along with opencdc.MetadataCollection set to the sheet title
+// and opencdc.MetadataCreatedAt.
+const (
+	// MetadataSpreadsheetID is the metadata key of the ID of the spreadsheet the record was read from.
+	MetadataSpreadsheetID = sheets.MetadataSpreadsheetID
+	// MetadataSpreadsheetTitle is the metadata key of the title of the spreadsheet the record was read from.
+	MetadataSpreadsheetTitle = sheets.MetadataSpreadsheetTitle
+	// MetadataSheetID is the metadata key of the gid of the sheet(tab) the record was read from.
+	MetadataSheetID = sheets.MetadataSheetID
+	// MetadataSheetTitle is the metadata key of the title of the sheet(tab) the record was read from.
+	MetadataSheetTitle = sheets.MetadataSheetTitle
+	// MetadataRowNumber is the metadata key of the 1-based number of the row the record was read from,
+	// the last known row number for a delete record.
+	MetadataRowNumber = sheets.MetadataRowNumber
+	// MetadataRange is the metadata key of the A1 notation of the row cells the record was read from,
+	// e.g. Sheet1!A5:D5. Not set on delete records.
+	MetadataRange = sheets.MetadataRange
+	// MetadataDriveFileID is the metadata key of the Drive file ID of the spreadsheet read from the driveFolderID folder.
+	MetadataDriveFileID = sheets.MetadataDriveFileID
+	// MetadataDriveFileName is the metadata key of the Drive file name of the spreadsheet read from the driveFolderID
+	// folder, as of the time the file was found in the folder.
+	MetadataDriveFileName = sheets.MetadataDriveFileName
+)
@@ -25,6 +25,15 @@ type SheetPosition struct {
 	RowOffset     int64  `json:"row_offset"`
 	SpreadsheetID string `json:"spreadsheet_id"`
 	SheetID       int64  `json:"sheet_id"`
+	// DeletedRow is the last known row number of the removed row of a delete record, the RowOffset being
+	// the row offset of the sheet, as the row isn't in the sheet anymore
+	DeletedRow int64 `json:"deleted_row,omitempty"`
+	// SheetOffsets are the row offsets of all the sheets(tabs) read by the source as of the record, by gid,
+	// the RowOffset and the SheetID being the row of the record itself
+	SheetOffsets map[int64]int64 `json:"sheet_offsets,omitempty"`
+	// Spreadsheets are the row offsets of the sheets of all the spreadsheets read by the source as of the record,
+	// by spreadsheet ID and gid, only set when several spreadsheets are read
+	Spreadsheets map[string]map[int64]int64 `json:"spreadsheets,omitempty"`
 }
 
 // ParseRecordPosition is used to parse the opencdc.Position to SheetPosition type
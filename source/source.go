@@ -17,14 +17,17 @@ package source
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	"github.com/conduitio-labs/conduit-connector-google-sheets/config"
 	"github.com/conduitio-labs/conduit-connector-google-sheets/sheets"
 	"github.com/conduitio-labs/conduit-connector-google-sheets/source/iterator"
 	"github.com/conduitio-labs/conduit-connector-google-sheets/source/position"
+	"github.com/conduitio-labs/conduit-connector-google-sheets/source/state"
 	cconfig "github.com/conduitio/conduit-commons/config"
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
+	"golang.org/x/oauth2"
 )
 
 // Source connector
@@ -33,11 +36,14 @@ type Source struct {
 
 	iterator Iterator
 	conf     Config
+	// tokenSource is the token source of the API requests, released on teardown
+	tokenSource oauth2.TokenSource
 }
 
 type Iterator interface {
 	HasNext() bool
 	Next(ctx context.Context) (opencdc.Record, error)
+	Ack(ctx context.Context) error
 	Stop(ctx context.Context)
 }
 
@@ -47,21 +53,23 @@ func NewSource() sdk.Source {
 
 // Parameters returns a map of named config.Parameters that describe how to configure the Source.
 func (s *Source) Parameters() cconfig.Parameters {
-	return map[string]cconfig.Parameter{
-		config.KeyCredentialsFile: {
+	params := config.ClientParameters()
+	maps.Copy(params, map[string]cconfig.Parameter{
+		config.KeySheetURL: {
 			Default:     "",
-			Description: "path to credentials.json file used",
-			Validations: []cconfig.Validation{cconfig.ValidationRequired{}},
+			Description: "Google sheet url, or the spreadsheet ID, to fetch the records from, or a comma separated list of them. Required unless driveFolderID is set",
 		},
-		config.KeyTokensFile: {
+		config.KeyDriveFolderID: {
 			Default:     "",
-			Description: "path to token.json file containing a json with atleast refresh_token.",
-			Validations: []cconfig.Validation{cconfig.ValidationRequired{}},
+			Description: "Google Drive folder url, or the folder ID, to read every spreadsheet in, including the spreadsheets added later, mutually exclusive with sheetsURL. Requires the drive.metadata.readonly scope",
 		},
-		config.KeySheetURL: {
+		config.KeySheetID: {
+			Default:     "",
+			Description: "gid of the sheet to fetch the records from, if not set in the sheet url",
+		},
+		config.KeySheetName: {
 			Default:     "",
-			Description: "Google sheet url to fetch the records from",
-			Validations: []cconfig.Validation{cconfig.ValidationRequired{}},
+			Description: "name of the sheet to fetch the records from, the first sheet is used if neither the gid nor the name is set",
 		},
 		KeyPollingPeriod: {
 			Default:     "6s",
@@ -75,7 +83,100 @@ func (s *Source) Parameters() cconfig.Parameters {
 			Default:     "FORMATTED_VALUE",
 			Description: "Format of the dynamic/reference data. Valid values: FORMATTED_VALUE, UNFORMATTED_VALUE, FORMULA",
 		},
-	}
+		KeyHeaderRow: {
+			Default:     "0",
+			Description: "1-based row number of the header row providing the field names of structured records, 0 to emit rows as JSON arrays",
+		},
+		KeyTypedValues: {
+			Default:     "false",
+			Description: "Convert the cell values to numbers and booleans, and date serials to timestamps with the SERIAL_NUMBER dateTimeRenderOption, using the cells' number format",
+		},
+		KeyDetectUpdates: {
+			Default:     "false",
+			Description: "Scan the whole sheet on each poll, emitting the changed rows as update records. Requires stateFile",
+		},
+		KeyDetectDeletes: {
+			Default:     "false",
+			Description: "Scan the whole sheet on each poll, emitting the removed rows as delete records. Requires rowIdentityColumn or keyColumns, and stateFile",
+		},
+		KeyRowIdentityColumn: {
+			Default:     "",
+			Description: "Header name or column letter of the column identifying the rows, e.g. an ID column, used to follow rows when rows are inserted or removed",
+		},
+		KeyKeyColumns: {
+			Default:     "",
+			Description: "Comma separated header names or column letters of the columns holding the record keys, e.g. region,id. Default: the row number",
+		},
+		KeyEmptyKeyPolicy: {
+			Default:     "hold",
+			Description: "Handling of the rows with a blank key cell, any blank cell of a composite key counts. Valid values: hold, error, skip. With hold, the row offset stays before the first row with a blank key cell, the row being read again on the next poll until its key is filled",
+		},
+		KeyStateFile: {
+			Default:     "",
+			Description: "path to the local file persisting the last known content of the rows, used to detect the changed and removed rows",
+		},
+		KeySheets: {
+			Default:     "",
+			Description: "Comma separated titles, or gid=<gid>, of the sheets(tabs) to read with a single request per poll, e.g. Orders,gid=1234. Default: the sheet of sheetsURL, sheetID or sheetName",
+		},
+		KeyAllSheets: {
+			Default:     "false",
+			Description: "Read all the sheets(tabs) of the spreadsheet, including the sheets added later",
+		},
+		KeyIncludeSheets: {
+			Default:     "",
+			Description: "Regular expression the titles of the sheets read with allSheets must match, e.g. ^2024-",
+		},
+		KeyExcludeSheets: {
+			Default:     "",
+			Description: "Regular expression the titles of the sheets read with allSheets mustn't match, e.g. ^_",
+		},
+		KeySheetsRefreshPeriod: {
+			Default:     "1m",
+			Description: "Period of the discovery of the added and removed sheets with allSheets, and spreadsheets with driveFolderID",
+		},
+		KeyRange: {
+			Default:     "",
+			Description: "Range of the cells read in A1 notation, e.g. B:F or Data!A2:K, the sheet name selecting the sheet. Default: the whole sheet",
+		},
+		KeyStartColumnIndex: {
+			Default:     "",
+			Description: "0-based index of the first column read, an alternative to range. Default: 0",
+		},
+		KeyEndColumnIndex: {
+			Default:     "",
+			Description: "0-based index of the column after the last column read, an alternative to range. Default: the last column",
+		},
+		KeyNamedRange: {
+			Default:     "",
+			Description: "Named range to read instead of a sheet, followed wherever it's moved, headerRow and the positions being relative to the range",
+		},
+		KeyBatchSize: {
+			Default:     "",
+			Description: "Maximum number of rows fetched per request, the next rows of a large sheet being fetched right away. Default: all the rows",
+		},
+		KeySnapshotConcurrency: {
+			Default:     "0",
+			Description: "Maximum number of pages of batchSize rows fetched concurrently by the snapshot of the sheets on start, 0 to disable the snapshot",
+		},
+		KeySnapshotRequestsPerMinute: {
+			Default:     "",
+			Description: "Maximum rate of the page requests of the snapshot, per minute. Default: 60",
+		},
+		KeyCreatedAtColumn: {
+			Default:     "",
+			Description: "Header name or column letter of the column holding the records created-at time, e.g. Timestamp. Default: the fetch time",
+		},
+		KeyCreatedAtLayout: {
+			Default:     "",
+			Description: "Go time layout of the created-at cells. Default: empty, the Forms timestamp format " + sheets.DefaultCreatedAtLayout,
+		},
+		KeyCreatedAtTimezone: {
+			Default:     "",
+			Description: "IANA time zone of the created-at cells without zone, e.g. Europe/Paris. Default: the spreadsheet time zone",
+		},
+	})
+	return params
 }
 
 // Configure validates the passed config and prepares the source connector
@@ -95,17 +196,73 @@ func (s *Source) Open(ctx context.Context, rp opencdc.Position) error {
 		return fmt.Errorf("couldn't parse position: %w", err)
 	}
 
-	s.iterator, err = iterator.NewSheetsIterator(ctx, pos,
-		sheets.BatchReaderArgs{
-			OAuthConfig:          s.conf.OAuthConfig,
-			OAuthToken:           s.conf.OAuthToken,
+	clientArgs, err := sheets.NewClientArgs(ctx, s.conf.Config)
+	if err != nil {
+		return err
+	}
+	s.tokenSource = clientArgs.TokenSource
+
+	var stateStore *state.Store
+	if s.conf.DetectUpdates || s.conf.DetectDeletes {
+		stateStore = state.NewStore(s.conf.StateFile)
+	}
+
+	readerArgs := sheets.MultiReaderArgs{
+		BatchReaderArgs: sheets.BatchReaderArgs{
+			ClientArgs:           clientArgs,
 			SpreadsheetID:        s.conf.GoogleSpreadsheetID,
 			SheetID:              s.conf.GoogleSheetID,
+			SheetName:            s.conf.GoogleSheetName,
 			DateTimeRenderOption: s.conf.DateTimeRenderOption,
 			ValueRenderOption:    s.conf.ValueRenderOption,
 			PollingPeriod:        s.conf.PollingPeriod,
+			HeaderRow:            s.conf.HeaderRow,
+			TypedValues:          s.conf.TypedValues,
+			DetectUpdates:        s.conf.DetectUpdates,
+			DetectDeletes:        s.conf.DetectDeletes,
+			RowIdentityColumn:    s.conf.RowIdentityColumn,
+			KeyColumns:           s.conf.KeyColumns,
+			SkipEmptyKeys:        s.conf.EmptyKeyPolicy == EmptyKeyPolicySkip,
+			HoldEmptyKeys:        s.conf.EmptyKeyPolicy == EmptyKeyPolicyHold,
+			CreatedAtColumn:      s.conf.CreatedAtColumn,
+			CreatedAtLayout:      s.conf.CreatedAtLayout,
+			CreatedAtLocation:    s.conf.CreatedAtLocation,
+			Range:                s.conf.Range,
+			NamedRange:           s.conf.NamedRange,
+			BatchSize:            s.conf.BatchSize,
 		},
-	)
+		Sheets:              s.conf.Sheets,
+		AllSheets:           s.conf.AllSheets,
+		IncludeSheets:       s.conf.IncludeSheets,
+		ExcludeSheets:       s.conf.ExcludeSheets,
+		SheetsRefreshPeriod: s.conf.SheetsRefreshPeriod,
+		Snapshot:            s.conf.Snapshot,
+	}
+	if s.conf.DriveFolderID != "" {
+		folder, err := sheets.NewDriveFolder(ctx, sheets.DriveFolderArgs{
+			ClientArgs: clientArgs,
+			FolderID:   s.conf.DriveFolderID,
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't create a drive folder client: %w", err)
+		}
+		s.iterator, err = iterator.NewDriveFolderIterator(ctx, pos, folder, readerArgs, stateStore)
+		if err != nil {
+			return fmt.Errorf("couldn't create a iterator: %w", err)
+		}
+		return nil
+	}
+
+	// the spreadsheets read with the same options, each one with its own gid if set in the sheets url
+	args := []sheets.MultiReaderArgs{readerArgs}
+	for _, spreadsheet := range s.conf.ExtraSpreadsheets {
+		spreadsheetArgs := readerArgs
+		spreadsheetArgs.SpreadsheetID = spreadsheet.SpreadsheetID
+		spreadsheetArgs.SheetID = spreadsheet.SheetID
+		args = append(args, spreadsheetArgs)
+	}
+
+	s.iterator, err = iterator.NewSheetsIterator(ctx, pos, args, stateStore)
 
 	if err != nil {
 		return fmt.Errorf("couldn't create a iterator: %w", err)
@@ -134,16 +291,21 @@ func (s *Source) Teardown(ctx context.Context) error {
 	if s.iterator != nil {
 		s.iterator.Stop(ctx)
 	}
+	if s.tokenSource != nil {
+		config.ReleaseTokenSource(s.tokenSource)
+		s.tokenSource = nil
+	}
 	return nil
 }
 
 // Ack is called by the conduit server after the record has been successfully processed by all destination connectors
-// We do not need to send any ack to Google sheets as we poll the Sheets API for data, so there is no data to be ack'd
+// We do not need to send any ack to Google sheets as we poll the Sheets API for data, so there is no data to be ack'd,
+// the iterator saves the row states once the records of a poll are acked
 func (s *Source) Ack(ctx context.Context, tp opencdc.Position) error {
 	pos, err := position.ParseRecordPosition(tp)
 	if err != nil {
 		sdk.Logger(ctx).Error().Err(err).Msg("invalid position received")
 	}
 	sdk.Logger(ctx).Trace().Int64("row_offset", pos.RowOffset).Msg("message ack received")
-	return nil
+	return s.iterator.Ack(ctx)
 }
@@ -0,0 +1,114 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/conduitio-labs/conduit-connector-google-sheets/internal/fileutil"
+)
+
+// Row is the last known content of a sheet row
+type Row struct {
+	// Fingerprint is the hex encoded sha256 hash of the Payload
+	Fingerprint string `json:"fingerprint"`
+	// Payload is the JSON encoded record payload of the row
+	Payload json.RawMessage `json:"payload"`
+	// Identity is the value of the row identity column, used to follow the row when rows are inserted or deleted,
+	// empty if the row is identified by its row number
+	Identity string `json:"identity,omitempty"`
+	// Key is the JSON encoded record key of the row, empty if the row number is the record key
+	Key json.RawMessage `json:"key,omitempty"`
+}
+
+// NewRow returns the Row of the JSON encoded record payload
+func NewRow(payload []byte) Row {
+	return Row{
+		Fingerprint: Fingerprint(payload),
+		Payload:     payload,
+	}
+}
+
+// Fingerprint returns the hex encoded sha256 hash of the JSON encoded record payload
+func Fingerprint(payload []byte) string {
+	hash := sha256.Sum256(payload)
+	return hex.EncodeToString(hash[:])
+}
+
+// State is the row content of the sheets read by the source, persisted in the state file
+type State struct {
+	// Sheets are the rows of each sheet keyed by SheetKey, the rows are keyed by their 1-based row number
+	Sheets map[string]map[int64]Row `json:"sheets"`
+}
+
+// SheetKey returns the key of the sheet rows in the State
+func SheetKey(spreadsheetID string, sheetID int64) string {
+	return fmt.Sprintf("%s/%d", spreadsheetID, sheetID)
+}
+
+// Store loads and saves the State from the local state file
+type Store struct {
+	path string
+	// mu serializes the writes to the state file
+	mu sync.Mutex
+}
+
+// NewStore returns the Store of the state file at path
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load returns the State from the state file, an empty State if the file doesn't exist yet
+func (s *Store) Load() (*State, error) {
+	stateBytes, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &State{Sheets: make(map[string]map[int64]Row)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state file: %w", err)
+	}
+
+	state := &State{}
+	if err := json.Unmarshal(stateBytes, state); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal state file: %w", err)
+	}
+	if state.Sheets == nil {
+		state.Sheets = make(map[string]map[int64]Row)
+	}
+	return state, nil
+}
+
+// Save atomically replaces the state file with the State, readable by the owner only as it holds the sheets content
+func (s *Store) Save(state *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stateBytes, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("error marshaling state: %w", err)
+	}
+
+	if err := fileutil.WriteFileAtomic(s.path, stateBytes); err != nil {
+		return fmt.Errorf("error replacing state file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright © 2022 Meroxa, Inc. & Gophers Lab Technologies Pvt. Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := NewStore(path)
+
+	got, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, &State{Sheets: map[string]map[int64]Row{}}, got)
+
+	want := &State{Sheets: map[string]map[int64]Row{
+		SheetKey("dummy_spreadsheet", 1234): {
+			2: NewRow([]byte(`["a","b"]`)),
+			3: NewRow([]byte(`["c","d"]`)),
+		},
+	}}
+	assert.NoError(t, store.Save(want))
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	got, err = store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestStore_LoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`not json`), 0o600))
+
+	_, err := NewStore(path).Load()
+	assert.ErrorContains(t, err, "unable to unmarshal state file")
+}
+
+func TestFingerprint(t *testing.T) {
+	assert.Equal(t, Fingerprint([]byte(`["a"]`)), NewRow([]byte(`["a"]`)).Fingerprint)
+	assert.NotEqual(t, Fingerprint([]byte(`["a"]`)), Fingerprint([]byte(`["b"]`)))
+}